@@ -0,0 +1,114 @@
+package gorace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Adapts the untyped channel of a cancelable into a typed channel for the typed cancelable variants
+type adapter[T any] struct {
+	out     chan T
+	stop    chan struct{}
+	started sync.Once
+	stopped sync.Once
+}
+
+func newAdapter[T any]() *adapter[T] {
+	return &adapter[T]{out: make(chan T), stop: make(chan struct{})}
+}
+
+// Returns the typed channel, forwarding the results of src converted by convert on first use. A failed
+// conversion cancels src with the conversion error
+func (a *adapter[T]) receive(src *goCancelable, convert func(v interface{}) (T, error)) <-chan T {
+	a.started.Do(func() {
+		go a.forward(src, convert)
+	})
+	return a.out
+}
+
+// Forwards results until src closes. Once stopped the remaining results are discarded so a Send blocked
+// on src can't hold up its cancel
+func (a *adapter[T]) forward(src *goCancelable, convert func(v interface{}) (T, error)) {
+	defer close(a.out)
+	for v := range src.receiver() {
+		result, err := convert(v)
+		if err != nil {
+			go src.CancelCause(err)
+			continue
+		}
+		select {
+		case a.out <- result:
+		case <-a.stop:
+			for range src.send {
+			}
+			return
+		}
+	}
+}
+
+// Stops forwarding, results that weren't received yet are dropped
+func (a *adapter[T]) close() {
+	a.stopped.Do(func() {
+		close(a.stop)
+	})
+}
+
+// Implements the methods shared by the typed cancelable variants on top of a regular cancelable. T is the
+// type results are received as and C the variant's interface, which Start and StartBackground return
+type wrapper[T, C any] struct {
+	gc      *goCancelable
+	results *adapter[T]
+	self    C
+}
+
+// Creates the wrapper of a variant, self being the variant embedding it
+func newWrapper[T, C any](self C, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) *wrapper[T, C] {
+	return &wrapper[T, C]{gc: newGoCancelable(handler, opts...), results: newAdapter[T](), self: self}
+}
+
+// Cancel cancels the cancelable, results that weren't received yet are dropped
+func (w *wrapper[T, C]) Cancel() bool {
+	w.results.close()
+	return w.gc.Cancel()
+}
+
+// Receive returns the typed result channel. A result that isn't a T cancels the cancelable with an error
+// wrapping ErrUnexpectedType
+func (w *wrapper[T, C]) Receive() <-chan T {
+	return w.results.receive(w.gc, func(v interface{}) (T, error) {
+		result, ok := v.(T)
+		if !ok {
+			return result, fmt.Errorf("%w: got %T, want %T", ErrUnexpectedType, v, result)
+		}
+		return result, nil
+	})
+}
+
+// Start calls the handler if the cancelable has not been canceled or started
+func (w *wrapper[T, C]) Start(ctx context.Context) C {
+	w.gc.Start(ctx)
+	return w.self
+}
+
+// StartBackground calls Start on a goroutine with the specified context
+func (w *wrapper[T, C]) StartBackground(ctx context.Context) C {
+	w.gc.StartBackground(ctx)
+	return w.self
+}
+
+// LastResult returns the last successful result sent on the channel, or the zero value if it isn't a T
+func (w *wrapper[T, C]) LastResult() T {
+	result, _ := w.gc.LastResult().(T)
+	return result
+}
+
+// Cause returns the error the cancelable was canceled with, if any
+func (w *wrapper[T, C]) Cause() error {
+	return w.gc.Cause()
+}
+
+// IsCanceled returns true if the cancelable is already canceled otherwise returns false
+func (w *wrapper[T, C]) IsCanceled() bool {
+	return w.gc.IsCanceled()
+}
@@ -0,0 +1,40 @@
+package gorace
+
+import "time"
+
+// Sends the result without blocking, waiting an exponentially growing delay between attempts while the
+// channel is full. The lock is released while waiting so Cancel isn't held up. Requires locks prior to
+// this method call to remain concurrency-safe.
+func (gc *goCancelable) sendBackoff(result interface{}) {
+	select {
+	case gc.send <- result:
+		gc.delivered(result)
+		gc.backoffDelay = 0
+		return
+	default:
+	}
+	for {
+		if gc.backoffDelay == 0 {
+			gc.backoffDelay = gc.backoffBase
+		} else if gc.backoffDelay *= 2; gc.backoffDelay > gc.backoffMax {
+			gc.backoffDelay = gc.backoffMax
+		}
+		timer := time.NewTimer(gc.backoffDelay)
+		gc.mu.Unlock()
+		select {
+		case <-timer.C:
+		case <-gc.quit:
+			timer.Stop()
+		}
+		gc.mu.Lock()
+		if gc.canceled.Load() {
+			return
+		}
+		select {
+		case gc.send <- result:
+			gc.delivered(result)
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+// GoRaceBatch creates a cancelable that coalesces sent results into []interface{} batches. A batch is
+// sent once it holds maxBatch results or maxWait has elapsed since its first result. The partial batch is
+// sent when the handler returns, and on Cancel if the channel has room for it. Cancel can't wait for room,
+// so otherwise its results are counted as dropped
+func GoRaceBatch(maxBatch int, maxWait time.Duration, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	gc := newGoCancelable(handler, opts...)
+	if maxBatch < 1 {
+		maxBatch = 1
+	}
+	gc.batchSize, gc.batchWait = maxBatch, maxWait
+	return gc.handle()
+}
+
+// Adds a result to the pending batch, sending it once full and scheduling a flush if this is its first
+// result. Requires locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) batch(result interface{}) {
+	gc.pending = append(gc.pending, result)
+	if len(gc.pending) >= gc.batchSize {
+		if gc.batchTimer != nil {
+			gc.batchTimer.Stop()
+			gc.batchTimer = nil
+		}
+		gc.sendBatch()
+		return
+	}
+	if gc.batchTimer == nil {
+		gc.batchTimer = time.AfterFunc(gc.batchWait, gc.flushBatch)
+	}
+}
+
+// Sends the pending batch
+func (gc *goCancelable) flushBatch() {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.batchTimer != nil {
+		gc.batchTimer.Stop()
+		gc.batchTimer = nil
+	}
+	if gc.canceled.Load() || len(gc.pending) == 0 {
+		return
+	}
+	gc.sendBatch()
+}
+
+// Sends the pending batch, releasing the lock while waiting for room. Its results are counted as dropped if
+// the cancelable is canceled first. Requires locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) sendBatch() {
+	batch := gc.pending
+	gc.pending = nil
+	if !gc.push(batch) {
+		gc.dropped.Add(uint64(len(batch)))
+		return
+	}
+	gc.deliveredAll(batch, len(batch))
+}
+
+// Sends the pending batch without blocking while canceling, its results are counted as dropped if the
+// channel is full. Requires locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) dropBatch() {
+	if gc.batchTimer != nil {
+		gc.batchTimer.Stop()
+		gc.batchTimer = nil
+	}
+	if len(gc.pending) == 0 {
+		return
+	}
+	batch := gc.pending
+	gc.pending = nil
+	select {
+	case gc.send <- batch:
+		gc.deliveredAll(batch, len(batch))
+	default:
+		gc.dropped.Add(uint64(len(batch)))
+	}
+}
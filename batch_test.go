@@ -0,0 +1,77 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceBatch() {
+	cancelable := GoRaceBatch(10, time.Hour, func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 45; i++ {
+			cancelable.Send(i)
+		}
+	})
+	cancelable.Start(context.Background())
+
+	var sizes []int
+	for batch := range cancelable.Receive() {
+		sizes = append(sizes, len(batch.([]interface{})))
+	}
+
+	suite.Equal([]int{10, 10, 10, 10, 5}, sizes, "the partial batch should be sent when the handler returns")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceBatchMaxWait() {
+	cancelable := GoRaceBatch(10, 20*time.Millisecond, func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2)
+		<-ctx.Done()
+	})
+	cancelable.Start(context.Background())
+	defer cancelable.Cancel()
+
+	select {
+	case batch := <-cancelable.Receive():
+		suite.Equal([]interface{}{1, 2}, batch)
+	case <-time.After(time.Second):
+		suite.Fail("the batch should be sent once maxWait elapsed")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceBatchCancel() {
+	sent := make(chan struct{})
+	cancelable := GoRaceBatch(10, time.Hour, func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2)
+		close(sent)
+		<-ctx.Done()
+	})
+	cancelable.Start(context.Background())
+	<-sent
+
+	cancelable.Cancel()
+
+	suite.Equal([]interface{}{1, 2}, <-cancelable.Receive(), "the partial batch should be sent on Cancel")
+	_, ok := <-cancelable.Receive()
+	suite.False(ok, "cancelable.Receive() should be closed")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceBatchCancelFullChannel() {
+	sent := make(chan struct{})
+	cancelable := GoRaceBatch(2, time.Hour, func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2) // fills the channel
+		cancelable.Send(3)
+		close(sent)
+		<-ctx.Done()
+	})
+	cancelable.Start(context.Background())
+	<-sent
+
+	cancelable.Cancel()
+
+	suite.Equal([]interface{}{1, 2}, <-cancelable.Receive())
+	_, ok := <-cancelable.Receive()
+	suite.False(ok, "the partial batch should not wait for room")
+	suite.Equal(uint64(1), cancelable.Dropped(), "the results of the partial batch should be counted as dropped")
+}
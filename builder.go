@@ -0,0 +1,83 @@
+package gorace
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Builder configures a cancelable with chainable methods, as an alternative to passing options to GoRace.
+// Each method returns the builder and Build creates the cancelable
+type Builder struct {
+	handler  func(ctx context.Context, cancelable GoCancelable)
+	opts     []Option
+	onCancel []func()
+	err      error
+}
+
+// NewBuilder returns a builder for a cancelable
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Handler sets the handler of the cancelable
+func (b *Builder) Handler(handler func(ctx context.Context, cancelable GoCancelable)) *Builder {
+	b.handler = handler
+	return b
+}
+
+// Buffer makes the channel buffer n results, see WithBuffer
+func (b *Builder) Buffer(n int) *Builder {
+	if n < 0 {
+		b.fail(fmt.Errorf("negative buffer size %d", n))
+	}
+	return b.Option(WithBuffer(n))
+}
+
+// Timeout cancels the cancelable once d has elapsed since Start, see WithTimeout
+func (b *Builder) Timeout(d time.Duration) *Builder {
+	if d <= 0 {
+		b.fail(fmt.Errorf("non-positive timeout %v", d))
+	}
+	return b.Option(WithTimeout(d))
+}
+
+// Name sets the name identifying the cancelable, see WithName
+func (b *Builder) Name(name string) *Builder {
+	return b.Option(WithName(name))
+}
+
+// OnCancel registers fn to be called once the cancelable is canceled, see GoCancelable.OnCancel
+func (b *Builder) OnCancel(fn func()) *Builder {
+	b.onCancel = append(b.onCancel, fn)
+	return b
+}
+
+// Option applies opt to the cancelable, for configuration without a dedicated builder method
+func (b *Builder) Option(opt Option) *Builder {
+	b.opts = append(b.opts, opt)
+	return b
+}
+
+// Build creates the cancelable. It panics if the builder is misconfigured, for example without a handler,
+// since that can only be a programming error
+func (b *Builder) Build() GoCancelable {
+	if b.err == nil && b.handler == nil {
+		b.err = ErrNoHandler
+	}
+	if b.err != nil {
+		panic(fmt.Sprintf("gorace: Build: %v", b.err))
+	}
+	cancelable := GoRace(b.handler, b.opts...)
+	for _, fn := range b.onCancel {
+		cancelable.OnCancel(fn)
+	}
+	return cancelable
+}
+
+// Records the first misconfiguration to be reported by Build
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -0,0 +1,41 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceBuilder() {
+	canceled := make(chan struct{})
+	cancelable := NewBuilder().
+		Handler(func(ctx context.Context, cancelable GoCancelable) {
+			for i := 0; i < 16; i++ {
+				cancelable.Send(i)
+			}
+			<-ctx.Done()
+		}).
+		Buffer(16).
+		Timeout(50 * time.Millisecond).
+		Name("fetch").
+		OnCancel(func() { close(canceled) }).
+		Build()
+	cancelable.Start(context.Background())
+
+	suite.Equal("fetch", cancelable.Name())
+	suite.Eventually(func() bool { return cancelable.Snapshot().Pending == 16 }, time.Second, time.Millisecond, "the channel should buffer 16 results")
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		suite.Fail("the timeout should cancel the cancelable and call OnCancel")
+	}
+	suite.Equal(context.DeadlineExceeded, cancelable.Cause())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceBuilderMisconfigured() {
+	suite.PanicsWithValue("gorace: Build: gorace: cancelable has no handler", func() {
+		NewBuilder().Name("fetch").Build()
+	})
+	suite.Panics(func() {
+		NewBuilder().Handler(func(ctx context.Context, cancelable GoCancelable) {}).Buffer(-1).Build()
+	}, "a negative buffer size should be rejected")
+}
@@ -0,0 +1,61 @@
+package gorace
+
+import (
+	"context"
+	"sync"
+)
+
+// CancelGroup runs cancelables tied to a shared context, like errgroup.WithContext does for functions. The
+// first member canceled with an error, as with WithErrorStop, cancels the others. Members are canceled once
+// the group's context is done or Cancel is called
+type CancelGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+}
+
+// NewCancelGroup creates a group whose members are canceled once ctx is done
+func NewCancelGroup(ctx context.Context) *CancelGroup {
+	ctx, cancel := context.WithCancel(ctx)
+	return &CancelGroup{ctx: ctx, cancel: cancel}
+}
+
+// Go creates a cancelable tracked by the group and starts it with the group's context. Its results still
+// need to be received, or discarded with Drain
+func (g *CancelGroup) Go(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	c := GoRace(handler, append(append([]Option(nil), opts...), WithShutdownContext(g.ctx))...)
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		<-c.Done()
+		if err := c.Cause(); err != nil {
+			g.mu.Lock()
+			if g.err == nil {
+				g.err = err
+				g.cancel()
+			}
+			g.mu.Unlock()
+		}
+	}()
+	return c.Start(g.ctx)
+}
+
+// Wait blocks until every member's handler returned and returns the first error a member was canceled
+// with, which is the context's error if the group was canceled first
+func (g *CancelGroup) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err == nil {
+		g.err = g.ctx.Err()
+	}
+	g.cancel()
+	return g.err
+}
+
+// Cancel cancels every member of the group
+func (g *CancelGroup) Cancel() {
+	g.cancel()
+}
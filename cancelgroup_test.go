@@ -0,0 +1,55 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceCancelGroup() {
+	failure := errors.New("request failed")
+	release := make(chan struct{})
+	g := NewCancelGroup(context.Background())
+	slow := g.Go(func(ctx context.Context, cancelable GoCancelable) {
+		<-release
+	})
+	g.Go(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(failure)
+	}, WithErrorStop()).Drain()
+
+	suite.Eventually(slow.IsCanceled, time.Second, time.Millisecond, "the first error should cancel the other members")
+	suite.ErrorIs(slow.Cause(), context.Canceled)
+	close(release)
+	suite.ErrorIs(g.Wait(), failure, "g.Wait() should return the first error")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCancelGroupCancel() {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewCancelGroup(ctx)
+	members := []GoCancelable{
+		g.Go(func(ctx context.Context, cancelable GoCancelable) { <-ctx.Done() }),
+		g.Go(func(ctx context.Context, cancelable GoCancelable) { <-ctx.Done() }),
+	}
+	cancel()
+
+	suite.ErrorIs(g.Wait(), context.Canceled)
+	for _, member := range members {
+		suite.True(member.IsCanceled(), "members should be canceled with the group's context")
+	}
+
+	g = NewCancelGroup(context.Background())
+	member := g.Go(func(ctx context.Context, cancelable GoCancelable) { <-ctx.Done() })
+	g.Cancel()
+	suite.ErrorIs(g.Wait(), context.Canceled)
+	suite.True(member.IsCanceled(), "g.Cancel() should cancel every member")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCancelGroupSuccess() {
+	g := NewCancelGroup(context.Background())
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context, cancelable GoCancelable) {
+			cancelable.Send(true)
+		}).Drain()
+	}
+	suite.NoError(g.Wait(), "members completing normally should not be an error")
+}
@@ -0,0 +1,163 @@
+package gorace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RunUntil starts a fresh cancelable from factory and waits for it to complete, up to maxRuns times,
+// until accept returns true for the last result of a run. Each run is canceled before the next one is
+// created. Returns the accepted result, or the last result with ErrRunsExhausted, or ctx.Err() if ctx
+// is done first
+func RunUntil(ctx context.Context, factory func() GoCancelable, accept func(result interface{}) bool, maxRuns int) (interface{}, error) {
+	var result interface{}
+	for run := 0; run < maxRuns; run++ {
+		c := factory().Start(ctx)
+		result = nil
+	wait:
+		for {
+			select {
+			case r, ok := <-c.Receive():
+				if !ok {
+					break wait
+				}
+				result = r
+			case <-ctx.Done():
+				c.Drain()
+				c.Cancel()
+				return nil, ctx.Err()
+			}
+		}
+		c.Cancel()
+		if accept(result) {
+			return result, nil
+		}
+	}
+	return result, ErrRunsExhausted
+}
+
+// Select starts c with the background context and waits up to d for its first result. On timeout c is
+// canceled and timedOut is true. A cancelable closing without a result returns nil
+func Select(c GoCancelable, d time.Duration) (result interface{}, timedOut bool) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	return selectFirst(c.Start(context.Background()), timer.C)
+}
+
+// SelectContext is like Select but starts c with ctx and waits until ctx is done instead of a duration
+func SelectContext(ctx context.Context, c GoCancelable) (result interface{}, timedOut bool) {
+	return selectFirst(c.Start(ctx), ctx.Done())
+}
+
+// Waits for the first result of c or stop, canceling c if stop comes first. Drains c before canceling
+// so a handler sending at the same moment can't block the cancel
+func selectFirst[T any](c GoCancelable, stop <-chan T) (interface{}, bool) {
+	select {
+	case result := <-c.Receive():
+		return result, false
+	case <-stop:
+		c.Drain()
+		c.Cancel()
+		return nil, true
+	}
+}
+
+// AsErrgroupFunc returns a function for errgroup.Group.Go that starts c with the background context and
+// receives its results until it completes. The function returns the last result if it's an error, or
+// else the error c was canceled with
+func AsErrgroupFunc(c GoCancelable) func() error {
+	return func() error {
+		var last interface{}
+		for result := range c.Start(context.Background()).Receive() {
+			last = result
+		}
+		if err, ok := last.(error); ok {
+			return err
+		}
+		return c.Cause()
+	}
+}
+
+// ReceiveN receives exactly n results of c. If the channel closes first, the results received so far are
+// returned with an error wrapping ErrChannelClosed. If ctx is done first, c is canceled and ctx.Err() is
+// returned with the results received so far
+func ReceiveN(ctx context.Context, c GoCancelable, n int) ([]interface{}, error) {
+	results := make([]interface{}, 0, n)
+	for len(results) < n {
+		select {
+		case result, ok := <-c.Receive():
+			if !ok {
+				return results, fmt.Errorf("%w after %d of %d results", ErrChannelClosed, len(results), n)
+			}
+			results = append(results, result)
+		case <-ctx.Done():
+			c.Drain()
+			c.Cancel()
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// ForEach calls fn with each result of c until the channel closes, returning nil. If fn returns an error,
+// c is canceled with it as the cause unless it completed already, and the error is returned. If ctx is
+// done first, c is canceled and ctx.Err() is returned
+func ForEach(ctx context.Context, c GoCancelable, fn func(result interface{}) error) error {
+	for {
+		select {
+		case result, ok := <-c.Receive():
+			if !ok {
+				return nil
+			}
+			if err := fn(result); err != nil {
+				c.Drain()
+				c.CancelCause(err)
+				return err
+			}
+		case <-ctx.Done():
+			c.Drain()
+			c.Cancel()
+			return ctx.Err()
+		}
+	}
+}
+
+// Stopped timers reused by ReceiveTimeout
+var timers = sync.Pool{
+	New: func() interface{} {
+		timer := time.NewTimer(time.Hour)
+		timer.Stop()
+		return timer
+	},
+}
+
+// ReceiveTimeout waits up to d for the next result of c. ok is false if the channel closed or the wait
+// timed out, in which case timedOut is true. Unlike selecting on time.After, the timer is stopped and
+// reused, so calling this in a loop doesn't pile up timers
+func ReceiveTimeout(c GoCancelable, d time.Duration) (result interface{}, ok bool, timedOut bool) {
+	timer := timers.Get().(*time.Timer)
+	timer.Reset(d)
+	select {
+	case result, ok = <-c.Receive():
+		if !timer.Stop() {
+			// Doesn't block where timers no longer buffer a fired value
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+	case <-timer.C:
+		timedOut = true
+	}
+	timers.Put(timer)
+	return result, ok, timedOut
+}
+
+// ReceiveAs blocks for the next result of c and asserts it to T. ok is false if the channel closed or the
+// result is of another type, the result is consumed either way
+func ReceiveAs[T any](c GoCancelable) (T, bool) {
+	result, ok := (<-c.Receive()).(T)
+	return result, ok
+}
@@ -0,0 +1,212 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceRunUntil() {
+	runs := 0
+	var previous GoCancelable
+	result, err := RunUntil(context.Background(), func() GoCancelable {
+		if previous != nil {
+			suite.Equal(true, previous.IsCanceled(), "the previous run should be canceled")
+		}
+		runs++
+		run := runs
+		previous = GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			cancelable.Send(run)
+		})
+		return previous
+	}, func(result interface{}) bool {
+		return result == 3
+	}, 5)
+
+	suite.NoError(err)
+	suite.Equal(3, result)
+	suite.Equal(3, runs, "runs should stop once accepted")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRunUntilExhausted() {
+	result, err := RunUntil(context.Background(), func() GoCancelable {
+		return GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			cancelable.Send(false)
+		})
+	}, func(result interface{}) bool {
+		return result == true
+	}, 3)
+
+	suite.ErrorIs(err, ErrRunsExhausted)
+	suite.Equal(false, result, "the last result should be returned")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRunUntilContext() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := RunUntil(ctx, func() GoCancelable {
+		return GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			<-ctx.Done()
+		})
+	}, func(result interface{}) bool {
+		return true
+	}, 3)
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSelect() {
+	result, timedOut := Select(GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	}), time.Second)
+
+	suite.Equal(true, result)
+	suite.False(timedOut, "timedOut should be false")
+
+	slow := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	result, timedOut = Select(slow, 10*time.Millisecond)
+
+	suite.Nil(result)
+	suite.True(timedOut, "timedOut should be true")
+	suite.Equal(true, slow.IsCanceled(), "slow.IsCanceled() should be true after a timeout")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSelectContext() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+
+	result, timedOut := SelectContext(ctx, slow)
+
+	suite.Nil(result)
+	suite.True(timedOut, "timedOut should be true")
+	suite.Equal(true, slow.IsCanceled(), "slow.IsCanceled() should be true after ctx is done")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceAsErrgroupFunc() {
+	failure := errors.New("request failed")
+	succeeded := AsErrgroupFunc(GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	}))
+	failed := AsErrgroupFunc(GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		cancelable.Send(failure)
+	}))
+	timedOut := AsErrgroupFunc(GoRaceTimeout(10*time.Millisecond, func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	}))
+
+	suite.NoError(succeeded())
+	suite.Equal(failure, failed(), "the last error result should be returned")
+	suite.ErrorIs(timedOut(), context.DeadlineExceeded, "the cancel cause should be returned")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceReceiveN() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	}).Start(context.Background())
+
+	results, err := ReceiveN(context.Background(), cancelable, 2)
+	suite.NoError(err)
+	suite.Equal([]interface{}{0, 1}, results)
+
+	results, err = ReceiveN(context.Background(), cancelable, 2)
+	suite.ErrorIs(err, ErrChannelClosed, "the channel closing early should be an error")
+	suite.Equal([]interface{}{2}, results)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	}).Start(context.Background())
+	_, err = ReceiveN(ctx, slow, 1)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal(true, slow.IsCanceled(), "slow.IsCanceled() should be true once ctx is done")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceForEach() {
+	counting := func() GoCancelable {
+		return GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			for i := 0; i < 50; i++ {
+				cancelable.Send(i)
+			}
+		}).Start(context.Background())
+	}
+	var results []interface{}
+	err := ForEach(context.Background(), counting(), func(result interface{}) error {
+		results = append(results, result)
+		return nil
+	})
+	suite.NoError(err)
+	suite.Len(results, 50, "every result should be passed to fn")
+
+	failure := errors.New("bad result")
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for !cancelable.IsCanceled() {
+			cancelable.Send(true)
+		}
+	}).Start(context.Background())
+	calls := 0
+	err = ForEach(context.Background(), cancelable, func(result interface{}) error {
+		calls++
+		return failure
+	})
+	suite.ErrorIs(err, failure, "the error of fn should be returned")
+	suite.Equal(1, calls, "fn should not be called after an error")
+	suite.ErrorIs(cancelable.Cause(), failure, "the cancelable should be canceled with the error")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	}).Start(context.Background())
+	err = ForEach(ctx, slow, func(result interface{}) error { return nil })
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal(true, slow.IsCanceled(), "slow.IsCanceled() should be true once ctx is done")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceReceiveTimeout() {
+	release := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		<-release
+	}).Start(context.Background())
+
+	result, ok, timedOut := ReceiveTimeout(cancelable, time.Second)
+	suite.Equal(1, result)
+	suite.True(ok, "a result should be received")
+	suite.False(timedOut)
+
+	_, ok, timedOut = ReceiveTimeout(cancelable, 10*time.Millisecond)
+	suite.False(ok, "no result should be received")
+	suite.True(timedOut, "the wait should time out")
+
+	close(release)
+	_, ok, timedOut = ReceiveTimeout(cancelable, time.Second)
+	suite.False(ok, "the channel should be closed")
+	suite.False(timedOut, "a closed channel should not be a timeout")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceReceiveAs() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		cancelable.Send("not a bool")
+	}).Start(context.Background())
+
+	result, ok := ReceiveAs[bool](cancelable)
+	suite.True(ok, "a bool should be received")
+	suite.True(result)
+
+	result, ok = ReceiveAs[bool](cancelable)
+	suite.False(ok, "a string should not be received as a bool")
+	suite.False(result, "a mismatch should return the zero value")
+
+	_, ok = ReceiveAs[bool](cancelable)
+	suite.False(ok, "the channel should be closed")
+}
@@ -0,0 +1,75 @@
+package gorace
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrResultTooLarge is sent in place of results exceeding the
+	// WithResultMaxSize limit
+	ErrResultTooLarge = errors.New("gorace: result too large")
+	// ErrFieldNotFound is sent by Pluck for results without the plucked
+	// field
+	ErrFieldNotFound = errors.New("gorace: field not found")
+	// ErrUnexpectedType is the cause of typed cancelables receiving a
+	// result of another type
+	ErrUnexpectedType = errors.New("gorace: unexpected result type")
+	// ErrChannelClosed is returned by ReceiveN when the channel closes
+	// before enough results were received
+	ErrChannelClosed = errors.New("gorace: channel closed")
+	// ErrRunsExhausted is returned by RunUntil when no run produced an
+	// accepted result
+	ErrRunsExhausted = errors.New("gorace: runs exhausted")
+	// ErrAlreadyStarted is returned when starting a cancelable that was
+	// already started
+	ErrAlreadyStarted = errors.New("gorace: cancelable already started")
+	// ErrNoHandler is returned when starting a cancelable without a
+	// handler
+	ErrNoHandler = errors.New("gorace: cancelable has no handler")
+	// ErrAlreadyCanceled is returned when starting a cancelable that was
+	// already canceled
+	ErrAlreadyCanceled = errors.New("gorace: cancelable already canceled")
+	// ErrStageTimeout matches the *StageTimeoutError a ThenTimeout chain
+	// is canceled with
+	ErrStageTimeout = errors.New("gorace: stage timed out")
+)
+
+// PanicError is the cause of cancelables whose handler panicked. Value is
+// the value passed to panic and Stack the handler goroutine's stack trace
+type PanicError struct {
+	Name  string
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("gorace: handler of %q panicked: %v", e.Name, e.Value)
+	}
+	return fmt.Sprintf("gorace: handler panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// StageTimeoutError is the cause of ThenTimeout chains whose stage ran
+// longer than its timeout. Stage is the position of the stage in the
+// chain, 1 for the first Then
+type StageTimeoutError struct {
+	Stage   int
+	Timeout time.Duration
+}
+
+func (e *StageTimeoutError) Error() string {
+	return fmt.Sprintf("gorace: stage %d timed out after %v", e.Stage, e.Timeout)
+}
+
+// Is reports whether target is ErrStageTimeout
+func (e *StageTimeoutError) Is(target error) bool {
+	return target == ErrStageTimeout
+}
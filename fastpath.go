@@ -0,0 +1,70 @@
+package gorace
+
+import "time"
+
+// Reports whether Send may skip the lock. Options keeping per-result state under the lock, or delivering
+// results other than by putting them on the channel, take it for their bookkeeping but release it while
+// the channel is full, see push. Options are only set at construction so this can be read without the lock
+func (gc *goCancelable) lockFree() bool {
+	return gc.errCh == nil && gc.groupBy == nil && gc.batchSize == 0 && gc.backoffBase == 0 && !gc.spill &&
+		gc.sizeOf == nil && !gc.record && gc.replayN == 0 && gc.extension == 0 && gc.attempts == 0 &&
+		gc.watchdog == 0
+}
+
+// Sends the result without holding the lock, not even while waiting for room on the channel, so a slow
+// consumer doesn't hold up Cancel or other callers. Returns false if the result was dropped because the
+// cancelable is canceled, before or while waiting. Only bookkeeping that can't be done atomically takes the
+// lock, after the result was sent
+func (gc *goCancelable) offer(result interface{}) bool {
+	result = gc.prepare(result)
+	if !gc.pushUnlocked(result, nil) {
+		return false
+	}
+	gc.lastResult.Store(&result)
+	gc.deliveries.Add(1)
+	gc.pushes.Add(1)
+	if err, isErr := result.(error); isErr || gc.watched.Load() {
+		gc.mu.Lock()
+		if isErr && gc.firstErr == nil {
+			gc.firstErr = err
+		}
+		gc.notifyChanged()
+		gc.mu.Unlock()
+	}
+	return true
+}
+
+// Puts the result on the channel, giving up once the cancelable is canceled or expired fires, a nil expired
+// never does. The read lock keeps cancel from closing the channel in the meantime, cancel closes quit first
+// to release waiting senders. Should the channel be closed anyway, as a caller-owned channel closed behind
+// the cancelable's back, the panic is turned into a dropped result
+func (gc *goCancelable) pushUnlocked(result interface{}, expired <-chan time.Time) (sent bool) {
+	gc.closing.RLock()
+	defer gc.closing.RUnlock()
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+	if gc.canceled.Load() {
+		return false
+	}
+	select {
+	case gc.send <- result:
+		return true
+	case <-gc.quit:
+		return false
+	case <-expired:
+		return false
+	}
+}
+
+// Closes the channel returned by LastResultChanged, if any and not closed by cancel already. Requires locks
+// prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) notifyChanged() {
+	if gc.changed != nil && !gc.canceled.Load() {
+		close(gc.changed)
+		gc.changed = nil
+		gc.watched.Store(false)
+	}
+}
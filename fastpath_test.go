@@ -0,0 +1,116 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceSendCancelStress() {
+	for i := 0; i < 100; i++ {
+		cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			var wg sync.WaitGroup
+			for j := 0; j < 16; j++ {
+				wg.Add(1)
+				go func(j int) {
+					defer wg.Done()
+					for k := 0; k < 10; k++ {
+						cancelable.Send(j*10 + k)
+					}
+				}(j)
+			}
+			wg.Wait()
+		})
+		cancelable.Start(context.Background())
+		<-cancelable.Receive()
+
+		canceled := make(chan struct{})
+		go func() {
+			defer close(canceled)
+			cancelable.Cancel() // without Drain, blocked senders must not hold this up
+		}()
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			suite.FailNow("Cancel should not wait for blocked senders")
+		}
+		select {
+		case <-cancelable.Done():
+		case <-time.After(time.Second):
+			suite.FailNow("blocked senders should return once canceled")
+		}
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendClosedChannel() {
+	ch := make(chan interface{}, 1)
+	cancelable := GoRaceChan(ch, func(ctx context.Context, cancelable GoCancelable) {})
+	close(ch)
+
+	suite.NotPanics(func() {
+		cancelable.Send(1)
+	}, "a send on a channel closed behind the cancelable's back should be dropped")
+	_, dropped := cancelable.Stats()
+	suite.Equal(int64(1), dropped)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCancelWhileSendBlocked() {
+	// Each handler fills the channel and then blocks on the next send
+	blocked := func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(errors.New("first"))
+		cancelable.Send(errors.New("second"))
+	}
+	identity := func(v interface{}) interface{} { return v }
+	cases := map[string]func() GoCancelable{
+		"history": func() GoCancelable { return GoRace(blocked, WithHistory()) },
+		"replay":  func() GoCancelable { return GoRace(blocked, WithReplay(2)) },
+		"max size": func() GoCancelable {
+			return GoRace(blocked, WithResultMaxSize(8, func(v interface{}) int { return 1 }))
+		},
+		"watchdog":  func() GoCancelable { return GoRace(blocked, WithSendWatchdog(time.Hour)) },
+		"extension": func() GoCancelable { return GoRace(blocked, WithTimeout(time.Hour), WithDeadlineExtension(time.Hour)) },
+		"retry":     func() GoCancelable { return GoRaceRetry(2, nil, blocked) },
+		"grouping":  func() GoCancelable { return GoRace(blocked, WithResultGrouping(identity, time.Millisecond)) },
+		"batch":     func() GoCancelable { return GoRaceBatch(1, time.Hour, blocked) },
+		"error channel": func() GoCancelable {
+			return GoRace(blocked, WithResultErrorChannel(make(chan error)))
+		},
+		"total order": func() GoCancelable { return GoRace(blocked, WithTotalOrder()) },
+		"timeout": func() GoCancelable {
+			return GoRace(func(ctx context.Context, cancelable GoCancelable) {
+				cancelable.SendTimeout(1, time.Hour)
+				cancelable.SendTimeout(2, time.Hour)
+			}, WithHistory())
+		},
+		"listener": func() GoCancelable {
+			cancelable := New()
+			id, _ := cancelable.Listen()
+			suite.NoError(cancelable.SetHandler(func(ctx context.Context, cancelable GoCancelable) {
+				cancelable.SendTo(id, 1)
+				cancelable.SendTo(id, 2)
+			}))
+			return cancelable
+		},
+	}
+	for name, create := range cases {
+		cancelable := create().Start(context.Background())
+		time.Sleep(20 * time.Millisecond) // gives the second send time to block
+
+		canceled := make(chan struct{})
+		go func() {
+			defer close(canceled)
+			cancelable.Cancel()
+		}()
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			suite.FailNow("Cancel should not wait for a blocked sender", name)
+		}
+		select {
+		case <-cancelable.Done():
+		case <-time.After(time.Second):
+			suite.FailNow("a blocked sender should return once canceled", name)
+		}
+	}
+}
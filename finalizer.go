@@ -0,0 +1,35 @@
+package gorace
+
+// Sends the value of the finalizer as the last result of a handler that completed, waiting for room like
+// Send so it never takes the place of a pending result. Requires locks prior to this method call to remain
+// concurrency-safe.
+func (gc *goCancelable) finalizeCompleted() {
+	if gc.finalizer == nil || gc.finalized || gc.canceled.Load() {
+		return
+	}
+	gc.finalized = true
+	result := gc.prepare(gc.finalizer())
+	if !gc.push(result) {
+		gc.dropped.Add(1)
+		return
+	}
+	gc.delivered(result)
+}
+
+// Puts the value of the finalizer on the channel as the last result while canceling, if the channel has
+// room or a consumer is waiting on it. Otherwise it is counted as dropped, pending results are never
+// discarded for it. Requires locks and the closing lock prior to this method call to remain
+// concurrency-safe.
+func (gc *goCancelable) finalize() {
+	if gc.finalizer == nil || gc.finalized {
+		return
+	}
+	gc.finalized = true
+	result := gc.prepare(gc.finalizer())
+	select {
+	case gc.send <- result:
+		gc.delivered(result)
+	default:
+		gc.dropped.Add(1)
+	}
+}
@@ -56,7 +56,14 @@ package gorace
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"runtime/debug"
+	"runtime/pprof"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // GoCancelable contract
@@ -64,109 +71,1460 @@ type GoCancelable interface {
 	// Cancel closes the internal channel and returns true. If the
 	// cancelable is already canceled this returns false
 	Cancel() bool
+	// CancelDrain cancels the cancelable and returns the result that was
+	// buffered at cancel time, if any
+	CancelDrain() (result interface{}, ok bool)
+	// CloseSend marks the end of the results. Further sends are ignored and
+	// buffered results can still be received before the channel reports
+	// closed. The handler's context stays alive until it returns or Cancel
+	// is called
+	CloseSend()
+	// Shutdown cancels the cancelable and waits for the handler to return
+	// or ctx to be done, in which case ctx.Err() is returned
+	Shutdown(ctx context.Context) error
+	// CancelCause cancels the cancelable and records err as the reason.
+	// Returns false if the cancelable is already canceled
+	CancelCause(err error) bool
+	// Cause returns the error the cancelable was canceled with, if any
+	Cause() error
+	// Reason returns why the channel was closed
+	Reason() Reason
+	// Done returns a channel closed once the handler returned, or once the
+	// cancelable is canceled if it was never started
+	Done() <-chan struct{}
+	// CompletedNormally returns true if the handler returned without
+	// panicking and without the cancelable being canceled
+	CompletedNormally() bool
+	// OnCancel registers fn to be called once the cancelable is canceled,
+	// however that happens
+	OnCancel(fn func())
+	// OnComplete registers fn to be called once the cancelable completed,
+	// see ReasonCompleted
+	OnComplete(fn func())
+	// Derive creates a child cancelable that is canceled when this
+	// cancelable is canceled
+	Derive(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable
+	// AddChild registers a cancelable to be canceled when this cancelable
+	// is canceled
+	AddChild(child GoCancelable)
 	// Send a result to channel listeners. This method requires calling
-	// Cancel() manually when done to free up resources
+	// Cancel() manually when done to free up resources. A Send waiting
+	// for room on the channel drops the result once canceled
 	Send(result interface{})
+	// SendPriority queues a result to be sent before queued results of
+	// lower priority
+	SendPriority(result interface{}, priority int)
+	// SendAll sends each result in order until the cancelable is canceled
+	// and returns how many were sent
+	SendAll(results []interface{}) int
+	// SendStream sends every value received from ch until ch is closed,
+	// the cancelable is canceled or ctx is done
+	SendStream(ctx context.Context, ch <-chan interface{})
+	// Fail marks the current run of the handler as failed. Retrying
+	// cancelables run the handler again, otherwise err is sent once the
+	// handler returns
+	Fail(err error)
+	// SendTimeout sends a result to channel listeners but gives up after the
+	// specified duration. Returns true if the result was delivered
+	SendTimeout(result interface{}, d time.Duration) bool
+	// SendAndWait sends a result like Send and waits until it is received.
+	// Returns false if it was dropped or the cancelable was canceled first
+	SendAndWait(result interface{}) bool
 	// Receive returns the internal communication channel
 	Receive() <-chan interface{}
+	// HasReceiver reports whether a consumer likely receives the results,
+	// based on the channel having been handed out. It can't tell whether
+	// the consumer still receives
+	HasReceiver() bool
+	// Map returns a cancelable yielding fn(result) for each result of this
+	// cancelable. Starting or canceling it does the same to this cancelable
+	Map(fn func(result interface{}) interface{}) GoCancelable
+	// Filter returns a cancelable yielding only the results of this
+	// cancelable for which pred returns true
+	Filter(pred func(result interface{}) bool) GoCancelable
+	// Next blocks for the next result and returns false once the channel
+	// is closed
+	Next() (result interface{}, ok bool)
+	// Err returns the error that ended the results after Next returned
+	// false, nil if they ended normally
+	Err() error
+	// Until returns a cancelable yielding the results of this cancelable
+	// up to and including the first one for which pred returns true, then
+	// canceling both
+	Until(pred func(result interface{}) bool) GoCancelable
+	// Then returns a cancelable calling next for each result of this
+	// cancelable, the results of the returned cancelable are what next
+	// sends on it
+	Then(next func(ctx context.Context, result interface{}, cancelable GoCancelable)) GoCancelable
+	// ThenTimeout is like Then but cancels the chain with a
+	// *StageTimeoutError if a call of next runs longer than d
+	ThenTimeout(d time.Duration, next func(ctx context.Context, result interface{}, cancelable GoCancelable)) GoCancelable
+	// WaitWithResult waits for the next result. received is true if a value
+	// was received, canceled is true if the channel was closed instead, and
+	// both are false if ctx is done first
+	WaitWithResult(ctx context.Context) (value interface{}, received bool, canceled bool)
+	// Subscribe starts the cancelable if needed and calls fn for each
+	// result on a goroutine until the channel closes or ctx is done
+	Subscribe(ctx context.Context, fn func(result interface{})) GoCancelable
+	// Listen registers a subscriber and returns its id and channel. The
+	// channel only receives results sent to id with SendTo
+	Listen() (id int, results <-chan interface{})
+	// SendTo sends a result to the subscriber with the id returned by
+	// Listen. Other subscribers don't receive it
+	SendTo(id int, result interface{})
+	// Pending returns the number of results buffered in the channel
+	Pending() int
+	// Drain discards results on a goroutine until the channel closes.
+	// Drain followed by Cancel guarantees the handler can't stay blocked
+	// on Send
+	Drain()
 	// Start runs the userdefined handler func and returns the internal
-	// channel. The specified context is passed through to the handler func
+	// channel. The handler func gets a context derived from the specified
+	// context that is canceled along with the cancelable
 	Start(ctx context.Context) GoCancelable
+	// SetHandler replaces the handler. Returns an error if the cancelable
+	// has already been started or canceled
+	SetHandler(handler func(ctx context.Context, cancelable GoCancelable)) error
+	// Named sets the name of an idle cancelable
+	Named(name string) GoCancelable
+	// Name returns the name of the cancelable, empty if it wasn't named
+	Name() string
+	// Buffered replaces the channel of an idle cancelable with one
+	// buffering n results
+	Buffered(n int) GoCancelable
+	// Logged sets the logger of an idle cancelable
+	Logged(l *slog.Logger) GoCancelable
+	// StartE is like Start but returns ErrAlreadyCanceled or
+	// ErrAlreadyStarted when the handler isn't called
+	StartE(ctx context.Context) (GoCancelable, error)
+	// Run calls the handler synchronously and returns the last result
+	// once it returned
+	Run(ctx context.Context) interface{}
+	// MustStart is like Start but panics when the handler isn't called
+	MustStart(ctx context.Context) GoCancelable
+	// StartDetached is like Start but the handler context keeps the
+	// values of ctx without being canceled along with it
+	StartDetached(ctx context.Context) GoCancelable
+	// StartOnce is like Start but returns true only if this call started
+	// the handler
+	StartOnce(ctx context.Context) bool
 	// StartBackground starts the canceled on a goroutine. Equivalent to
 	// go cancelable.Start(ctx)
 	StartBackground(ctx context.Context) GoCancelable
-	// LastResult returns the last value sent successfully on the channel.
-	// Note this value isn't updated after Cancel() is called
+	// Replay sends every recorded result in order to dst. Results are only
+	// recorded with the Replayable option
+	Replay(dst chan<- interface{})
+	// History returns a copy of every recorded result in order. Results
+	// are only recorded with the WithHistory or Replayable option
+	History() []interface{}
+	// Deadline returns the time the cancelable will be canceled by its
+	// timeout or its handler context, whichever comes first. ok is false
+	// when there is no deadline
+	Deadline() (deadline time.Time, ok bool)
+	// StartedAt returns the time the handler was launched. ok is false if
+	// the cancelable wasn't started
+	StartedAt() (startedAt time.Time, ok bool)
+	// Elapsed returns how long the cancelable has been running, or ran for
+	// if it is canceled
+	Elapsed() time.Duration
+	// Remaining returns the time left before the deadline, or NoDeadline
+	Remaining() time.Duration
+	// ExtendDeadline moves the timeout of a started timeout cancelable to d
+	// from now. Does nothing for other cancelables
+	ExtendDeadline(d time.Duration)
+	// LastResult returns the last value sent successfully on the channel,
+	// whether or not a consumer received it yet. Note this value isn't
+	// updated after Cancel() is called
 	LastResult() interface{}
+	// LastResultChanged returns a channel closed once LastResult changes
+	// or the cancelable is canceled
+	LastResultChanged() <-chan struct{}
+	// Snapshot returns the current status of the cancelable
+	Snapshot() Status
+	// Dropped returns the number of results dropped by lossy sends, sends
+	// after a cancel, timed out sends and sends over the rate limit
+	Dropped() uint64
+	// Stats returns the number of results delivered and dropped so far,
+	// counting each result of a batch or group
+	Stats() (delivered, dropped int64)
+	// FirstError returns the first error sent by the handler
+	FirstError() error
+	// SetResult stores a named result, independently of the channel
+	SetResult(key string, value interface{})
+	// Results returns a copy of the named results
+	Results() map[string]interface{}
+	// LatestProduced returns the value of the most recent Send, whether or
+	// not a consumer received it yet
+	LatestProduced() interface{}
 	// IsCanceled returns true if the cancelable is canceled otherwise
 	// returns false
 	IsCanceled() bool
+	// ID returns the identifier assigned to the cancelable on creation
+	ID() uint64
+	// SourceID returns the ID of the cancelable the results originate
+	// from. Cancelables created by operators report their source's ID,
+	// others their own
+	SourceID() uint64
+	// Equal returns true if other is the same cancelable or both share the
+	// same source, so a cancelable created by an operator equals its source
+	Equal(other GoCancelable) bool
 }
 
+// Reason tells why the channel of a cancelable was closed
+type Reason int
+
+const (
+	// ReasonNone means the cancelable isn't canceled yet
+	ReasonNone Reason = iota
+	// ReasonCompleted means the handler returned or the results were ended
+	// with CloseSend
+	ReasonCompleted
+	// ReasonCanceled means the cancelable was canceled before completing
+	ReasonCanceled
+)
+
 // GoRace creates and returns a cancelable instance. The specified handler
-// will be called in Start
-func GoRace(handler func(ctx context.Context, cancelable GoCancelable)) GoCancelable {
+// will be called in Start. Options are applied in order
+func GoRace(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	return newGoCancelable(handler, opts...).handle()
+}
+
+// Creates the cancelable behind GoRace and the other constructors
+func newGoCancelable(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) *goCancelable {
 	send := make(chan interface{}, 1)
-	return &goCancelable{handler: handler, send: send}
+	gc := &goCancelable{handler: handler, send: send, quit: make(chan struct{}), done: make(chan struct{})}
+	gc.id = lastID.Add(1)
+	for _, opt := range opts {
+		opt(gc)
+	}
+	if gc.timeout == 0 {
+		gc.timeout = time.Duration(defaultTimeout.Load())
+	}
+	if gc.observer != nil {
+		gc.hooks = append(gc.hooks, func() {
+			gc.observer.Canceled(gc, gc.Cause())
+		})
+	}
+	if gc.registry != nil {
+		gc.registry.add(gc)
+	}
+	return gc
+}
+
+// GoRaceTimeout creates a cancelable that is canceled with context.DeadlineExceeded once d has elapsed
+// since Start. Equivalent to GoRace with WithTimeout(d)
+func GoRaceTimeout(d time.Duration, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	return GoRace(handler, append(append([]Option(nil), opts...), WithTimeout(d))...)
+}
+
+// GoRaceNamed creates a cancelable like GoRace with a name identifying it in logs and warnings
+func GoRaceNamed(name string, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	return GoRace(handler, opts...).Named(name)
+}
+
+// GoRaceWithContext creates a cancelable tied to ctx: it is canceled with the context's cause as soon as ctx
+// is done, even before Start, and Start(nil) runs the handler with ctx. A context passed to Start takes
+// precedence for the handler, the cancelable is still canceled once either context is done
+func GoRaceWithContext(ctx context.Context, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	gc := newGoCancelable(handler, append(append([]Option(nil), opts...), WithShutdownContext(ctx))...)
+	gc.defaultCtx = ctx
+	return gc.handle()
+}
+
+// GoRaceChan creates a cancelable sending its results on ch instead of a channel of its own. The cancelable
+// takes ownership of ch: it is closed when the cancelable is canceled, so nothing else may send on or
+// close it, and sharing ch between cancelables requires the others to be done before the first cancels
+func GoRaceChan(ch chan interface{}, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	gc := newGoCancelable(handler, opts...)
+	gc.send = ch
+	return gc.handle()
+}
+
+// New creates a cancelable without a handler. The handler needs to be set with SetHandler before Start
+func New(opts ...Option) GoCancelable {
+	return GoRace(nil, opts...)
+}
+
+// Last ID assigned to a cancelable
+var lastID atomic.Uint64
+
+// Timeout of cancelables constructed without WithTimeout, see SetDefaultTimeout
+var defaultTimeout atomic.Int64
+
+// SetDefaultTimeout sets a timeout applied to every cancelable constructed afterwards without WithTimeout,
+// canceling it with context.DeadlineExceeded once d has elapsed since Start. This is a global knob meant as a
+// hard safety ceiling for long-running services so no cancelable runs forever. Zero, the default, disables it
+func SetDefaultTimeout(d time.Duration) {
+	defaultTimeout.Store(int64(d))
 }
 
 // Implementation for the gorace framework
 type goCancelable struct {
+	id         uint64
 	handler    func(ctx context.Context, cancelable GoCancelable)
 	send       chan interface{}
-	canceled   bool
+	quit       chan struct{}
+	done       chan struct{}
+	canceled   atomic.Bool // Written under the lock, read lock-free by IsCanceled
 	started    bool
-	lastResult interface{}
+	ctx        context.Context
+	defaultCtx context.Context
+	stop       context.CancelCauseFunc
+	deadline   time.Time
+	startedAt  time.Time
+	stoppedAt  time.Time
+	timer      *time.Timer
+	unwatch    []func() bool
+	lastResult atomic.Pointer[interface{}] // Written lock-free by offer
+	history    []interface{}
+	deliveries atomic.Uint64 // Results delivered, batches and groups count each result in them
+	pushes     atomic.Uint64 // Values put on the channel, see SendAndWait
+	named      map[string]interface{}
+	changed    chan struct{}
+	watched    atomic.Bool // Set while changed is open, so offer knows to close it
+	receiving  atomic.Bool // Set once the channel was handed to a consumer, see HasReceiver
+	listeners  map[int]chan interface{}
+	sent       bool
+	finalized  bool
+	failure    error
+	firstErr   error
+	cause      error
+	reason     Reason
+	parent     *goCancelable
+	depth      int // Position in a Then chain, reported by StageTimeoutError
+	children   []GoCancelable
+	hooks      []func()
 	mu         sync.Mutex
+	closing    sync.RWMutex // Held by lock-free sends, cancel takes it to close the channel
+
+	// Options
+	name        string
+	logger      *slog.Logger
+	timestamp   bool
+	cascadeUp   bool
+	sink        func(v interface{}) error
+	record      bool
+	maxSize     int
+	sizeOf      func(v interface{}) int
+	timeout     time.Duration
+	groupBy     func(v interface{}) interface{}
+	flush       time.Duration
+	errCh       chan<- error
+	attempts    int
+	totalOrder  bool
+	cancelOnNil bool
+	sourceID    uint64
+	merge       MergeStrategy
+	dropExcess  bool
+	observer    Observer
+	leakCheck   bool
+	watchdog    time.Duration
+	lossy       bool
+	dropped     atomic.Uint64
+	registry    *Registry
+	errorStop   bool
+	finalizer   func() interface{}
+	extension   time.Duration
+	backoff     func(attempt int) time.Duration
+
+	// Backoff delivery
+	backoffBase  time.Duration
+	backoffMax   time.Duration
+	backoffDelay time.Duration
+
+	// Total order state
+	seq      atomic.Uint64
+	nextSeq  uint64
+	reorder  map[uint64]interface{}
+	ordering bool
+
+	// Grouping state
+	groupKeys  []interface{}
+	groups     map[interface{}][]interface{}
+	groupTimer *time.Timer
+
+	// Rate limiting state
+	limiter *tokenBucket
+
+	// Priority queue state
+	prio     priorityQueue
+	prioSeq  uint64
+	prioMu   sync.Mutex // Separate from the cancelable's lock so queueing never waits on a blocked send
+	prioWake chan struct{}
+	prioRoom chan struct{}
+	prioStop chan struct{}
+	prioDone chan struct{}
+	prioHeld int
+	prioOnce sync.Once
+
+	// Spillover
+	spill       bool
+	spillMax    int
+	spillPolicy SpillPolicy
+
+	// Replay state
+	replayN    int
+	replay     []interface{}
+	replayNext int
+
+	// Batching state
+	batchSize  int
+	batchWait  time.Duration
+	pending    []interface{}
+	batchTimer *time.Timer
 }
 
 // Cancel closes the send channel and sets the state to canceled
 func (gc *goCancelable) Cancel() bool {
+	return gc.CancelCause(nil)
+}
+
+// CancelCause closes the send channel, sets the state to canceled and stores the cause. Children are
+// canceled afterwards, and the parent too if the cancelable cascades up and the cause is not nil
+func (gc *goCancelable) CancelCause(err error) bool {
 	gc.mu.Lock()
-	defer gc.mu.Unlock()
-	return gc.cancel()
+	canceled := gc.cancel(err)
+	gc.mu.Unlock()
+	if canceled {
+		gc.propagate(err)
+	}
+	return canceled
+}
+
+// CancelDrain cancels the cancelable like Cancel, taking the result still buffered in the channel out
+// before closing it so the caller can salvage it. ok is false if nothing was buffered or the cancelable was
+// already canceled
+func (gc *goCancelable) CancelDrain() (interface{}, bool) {
+	gc.mu.Lock()
+	if gc.canceled.Load() {
+		gc.mu.Unlock()
+		return nil, false
+	}
+	var result interface{}
+	var ok bool
+	select {
+	case result, ok = <-gc.send:
+	default:
+	}
+	gc.cancel(nil)
+	gc.mu.Unlock()
+	gc.propagate(nil)
+	return result, ok
+}
+
+// Closes the send channel and sets the state to canceled, canceling the
+// handler's context as well even if the results were ended already with
+// CloseSend. Requires locks prior to this method call to remain
+// concurrency-safe.
+func (gc *goCancelable) cancel(cause error) bool {
+	canceled := gc.end(cause)
+	if gc.timer != nil {
+		gc.timer.Stop()
+	}
+	if gc.stop != nil {
+		gc.stop(cause) // Aborts the handler's work, telling it why through context.Cause
+	}
+	return canceled
 }
 
-// Closes the send channel and sets the state to canceled. Requires locks
-// prior to this method call to remain concurrency-safe.
-func (gc *goCancelable) cancel() bool {
-	if !gc.canceled {
-		gc.canceled = true
+// Closes the send channel and sets the state to canceled without touching
+// the handler's context or its timeout. Requires locks prior to this method
+// call to remain concurrency-safe.
+func (gc *goCancelable) end(cause error) bool {
+	if !gc.canceled.Load() {
+		gc.canceled.Store(true)
+		gc.cause = cause
+		gc.reason = ReasonCanceled
+		gc.stoppedAt = time.Now()
+		if gc.groupTimer != nil {
+			gc.groupTimer.Stop()
+		}
+		gc.dropBatch()
+		for _, stop := range gc.unwatch {
+			stop()
+		}
+		close(gc.quit)
+		gc.closing.Lock() // Waits for lock-free sends in flight
+		gc.finalize()
+		if gc.changed != nil {
+			close(gc.changed)
+		}
+		for _, listener := range gc.listeners {
+			close(listener)
+		}
 		close(gc.send)
+		gc.closing.Unlock()
+		if !gc.started {
+			close(gc.done) // There's no handler to wait for
+		}
 		return true
 	} else {
 		return false
 	}
 }
 
+// Shutdown discards pending results, cancels the cancelable and blocks until the handler goroutine returned.
+// Returns ctx.Err() if ctx is done first. Handlers that don't return after being canceled keep Shutdown
+// waiting, so pass a context with a deadline for teardown that must complete
+func (gc *goCancelable) Shutdown(ctx context.Context) error {
+	gc.Drain()
+	gc.Cancel()
+	select {
+	case <-gc.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseSend ends the results cleanly, as when the handler returns: grouped results are delivered first,
+// then further sends become no-ops and the channel is closed once sends in flight are done. Unlike a hard
+// Cancel, producers that finished call this so consumers can drain everything that's buffered. The
+// handler's context isn't canceled, so work it still has to do after its last result isn't aborted; Cancel
+// still cancels it
+func (gc *goCancelable) CloseSend() {
+	gc.complete(false)
+}
+
+// Delivers any grouped, batched or queued results and cancels the cancelable once the handler returned
+func (gc *goCancelable) finish() {
+	gc.complete(true)
+}
+
+// Delivers any grouped, batched or queued results and ends the results, canceling the handler's context
+// as well if stop is set
+func (gc *goCancelable) complete(stop bool) {
+	gc.flushGroups()
+	gc.flushBatch()
+	gc.flushPriority()
+	gc.mu.Lock()
+	gc.finalizeCompleted()
+	var canceled bool
+	if stop {
+		canceled = gc.cancel(nil)
+	} else {
+		canceled = gc.end(nil)
+	}
+	if canceled {
+		gc.reason = ReasonCompleted
+	}
+	gc.mu.Unlock()
+	if canceled {
+		gc.propagate(nil)
+	}
+}
+
+// Cancels the cancelable with the context's cause once ctx is done
+func (gc *goCancelable) watch(ctx context.Context) {
+	gc.unwatch = append(gc.unwatch, context.AfterFunc(ctx, func() {
+		gc.CancelCause(context.Cause(ctx))
+	}))
+}
+
+// Propagates a cancel to the related cancelables. Must be called without holding locks since the
+// related cancelables may propagate back to this one
+func (gc *goCancelable) propagate(cause error) {
+	gc.logStopped(cause)
+	gc.mu.Lock()
+	children, hooks := gc.children, gc.hooks
+	gc.children, gc.hooks = nil, nil
+	gc.mu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+	for _, child := range children {
+		child.Cancel()
+	}
+	if gc.cascadeUp && cause != nil && gc.parent != nil {
+		gc.parent.CancelCause(cause)
+	}
+}
+
+// Calls fn once the cancelable is canceled, right away if it already is
+func (gc *goCancelable) afterCancel(fn func()) {
+	gc.mu.Lock()
+	if !gc.canceled.Load() {
+		gc.hooks = append(gc.hooks, fn)
+		gc.mu.Unlock()
+		return
+	}
+	gc.mu.Unlock()
+	fn()
+}
+
+// Done returns the channel closed once the handler returned and the cancelable was cleaned up
+func (gc *goCancelable) Done() <-chan struct{} {
+	return gc.done
+}
+
+// CompletedNormally returns true if the cancelable completed, which is the case when the handler returned
+// on its own. Handler panics cancel the cancelable with a *PanicError cause instead
+func (gc *goCancelable) CompletedNormally() bool {
+	return gc.Reason() == ReasonCompleted
+}
+
+// OnCancel calls fn exactly once when the cancelable is canceled, right away if it already is. It is called
+// without holding locks, after the channel is closed and before children are canceled
+func (gc *goCancelable) OnCancel(fn func()) {
+	gc.afterCancel(fn)
+}
+
+// OnComplete calls fn exactly once if the cancelable completes, right away if it already did. It is never
+// called for cancelables canceled before completing
+func (gc *goCancelable) OnComplete(fn func()) {
+	gc.afterCancel(func() {
+		if gc.Reason() == ReasonCompleted {
+			fn()
+		}
+	})
+}
+
+// Cause returns the error passed to CancelCause or nil
+func (gc *goCancelable) Cause() error {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.cause
+}
+
+// Reason returns ReasonCompleted if the channel was closed because the handler returned, ReasonCanceled if
+// it was closed by a cancel and ReasonNone while it's open
+func (gc *goCancelable) Reason() Reason {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.reason
+}
+
+// Derive creates a child cancelable with the specified handler and options. Canceling this cancelable
+// cancels the child. The child needs to be started separately
+func (gc *goCancelable) Derive(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	child := newGoCancelable(handler, opts...)
+	child.parent = gc
+	gc.mu.Lock()
+	canceled := gc.canceled.Load()
+	if !canceled {
+		gc.children = append(gc.children, child)
+	}
+	gc.mu.Unlock()
+	if canceled {
+		child.Cancel()
+	}
+	return child.handle()
+}
+
+// AddChild cancels child when this cancelable is canceled, right away if it already is. Adding the
+// cancelable itself or a child that's already registered does nothing. Cycles are fine since canceling an
+// already canceled cancelable doesn't propagate again
+func (gc *goCancelable) AddChild(child GoCancelable) {
+	if child == nil || child.ID() == gc.id {
+		return
+	}
+	gc.mu.Lock()
+	canceled := gc.canceled.Load()
+	if !canceled {
+		for _, registered := range gc.children {
+			if registered.ID() == child.ID() {
+				gc.mu.Unlock()
+				return
+			}
+		}
+		gc.children = append(gc.children, child)
+	}
+	gc.mu.Unlock()
+	if canceled {
+		child.Cancel()
+	}
+}
+
 // IsCanceled returns true if the cancelable is already canceled otherwise returns false
 func (gc *goCancelable) IsCanceled() bool {
+	return gc.canceled.Load()
+}
+
+// Send stores the last result and sends the result on the cancelable's channel. Cancel can never close the
+// channel while a result is in flight, which makes it safe to call Send and Cancel concurrently, including
+// from inside the handler. A Send waiting for room never holds the lock and drops its result once canceled
+func (gc *goCancelable) Send(result interface{}) {
+	gc.sendResult(result)
+}
+
+// Sends the result like Send, applying every send option. Returns false if the result wasn't sent, because
+// it was dropped or a nil result canceled the cancelable
+func (gc *goCancelable) sendResult(result interface{}) bool {
+	if result == nil && gc.cancelOnNil {
+		gc.finish()
+		return false
+	}
+	if gc.lossy {
+		if !gc.sendLossy(result) {
+			gc.logEvent(slog.LevelDebug, "gorace: result dropped")
+			return false
+		}
+		return true
+	}
+	if gc.limiter != nil && !gc.throttle() {
+		return false
+	}
+	sent := true
+	if gc.totalOrder {
+		sent = gc.sendOrdered(result)
+	} else if !gc.sendOpen(result) {
+		sent = false
+		gc.dropped.Add(1)
+		gc.logEvent(slog.LevelDebug, "gorace: result dropped")
+	} else if gc.observer != nil {
+		gc.observer.Sent(gc, result)
+	}
+	if err, ok := result.(error); ok && gc.errorStop {
+		gc.CancelCause(err)
+	}
+	return sent
+}
+
+// Sends the result unless the cancelable is canceled, in which case false is returned. Takes the lock
+// only if an option needs it, see offer
+func (gc *goCancelable) sendOpen(result interface{}) bool {
+	if gc.lockFree() {
+		return gc.offer(result)
+	}
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
-	return gc.canceled
+	return gc.sendLocked(result)
 }
 
-// Send stores the last result and sends the result on the cancelable's channel
-func (gc *goCancelable) Send(result interface{}) {
+// Sends the result if the cancelable isn't canceled, returning false if it was dropped because of a cancel.
+// The lock is released while waiting for room, the bookkeeping is done under it once the result was sent.
+// Requires locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) sendLocked(result interface{}) bool {
+	if gc.canceled.Load() {
+		return false
+	}
+	gc.sent = true
+	if err, ok := result.(error); ok && gc.errCh != nil {
+		if !gc.pushErr(err, nil) {
+			return false
+		}
+		if gc.firstErr == nil {
+			gc.firstErr = err
+		}
+		return true
+	}
+	result = gc.prepare(result)
+	if gc.groupBy != nil {
+		gc.group(result)
+		return true
+	}
+	if gc.batchSize > 0 {
+		gc.batch(result)
+		return true
+	}
+	if gc.backoffBase > 0 {
+		gc.sendBackoff(result)
+		return true
+	}
+	if gc.spill {
+		gc.spillover(result)
+		return true
+	}
+	if !gc.push(result) {
+		return false
+	}
+	gc.delivered(result)
+	return true
+}
+
+// Sends the error on the error channel, releasing the lock while waiting like push. Returns false if the
+// cancelable was canceled or expired fired first. Requires locks prior to this method call to remain
+// concurrency-safe.
+func (gc *goCancelable) pushErr(err error, expired <-chan time.Time) bool {
+	gc.mu.Unlock()
+	defer gc.mu.Lock()
+	select {
+	case gc.errCh <- err:
+		return true
+	case <-gc.quit:
+		return false
+	case <-expired:
+		return false
+	}
+}
+
+// SendTimeout attempts to send the result on the cancelable's channel for at most d, without holding the
+// lock while waiting. The last result is only stored if the send succeeds. Returns false on timeout or if
+// the cancelable is canceled
+func (gc *goCancelable) SendTimeout(result interface{}, d time.Duration) bool {
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
-	if !gc.canceled {
-		gc.lastResult = result
-		gc.send <- result // this can block
+	if gc.canceled.Load() {
+		gc.dropped.Add(1)
+		return false
+	}
+	gc.sent = true
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	if err, ok := result.(error); ok && gc.errCh != nil {
+		if !gc.pushErr(err, timer.C) {
+			gc.dropped.Add(1)
+			return false
+		}
+		if gc.firstErr == nil {
+			gc.firstErr = err
+		}
+		return true
+	}
+	result = gc.prepare(result)
+	if gc.groupBy != nil {
+		gc.group(result)
+		return true
+	}
+	if gc.batchSize > 0 {
+		gc.batch(result)
+		return true
+	}
+	gc.mu.Unlock()
+	sent := gc.pushUnlocked(result, timer.C)
+	gc.mu.Lock()
+	if !sent {
+		gc.dropped.Add(1)
+		return false
+	}
+	gc.delivered(result)
+	return true
+}
+
+// SendAndWait sends the result like Send and then waits until the consumer received it, so a handler
+// producing one value at a time can pause until the consumer asked for the next, like a generator. Returns
+// false if the result was dropped, or if the cancelable was canceled before the result was received. Results
+// sent later by other goroutines aren't waited for. With GoRaceBatch, WithResultGrouping or WithSpillover
+// a result that is still held back counts as received once everything delivered before it was
+func (gc *goCancelable) SendAndWait(result interface{}) bool {
+	if !gc.sendResult(result) {
+		return false
+	}
+	// Everything put on the channel up to and including this result has to be taken off it
+	position := int64(gc.pushes.Load())
+	received := func() bool {
+		return int64(gc.pushes.Load())-int64(len(gc.send)) >= position
+	}
+	if received() {
+		return true
+	}
+	// Channels don't report receives, check again with growing pauses
+	delay := 50 * time.Microsecond
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for !received() {
+		select {
+		case <-timer.C:
+		case <-gc.quit:
+			return false
+		}
+		if delay < 5*time.Millisecond {
+			delay *= 2
+		}
+		timer.Reset(delay)
+	}
+	return true
+}
+
+// SendAll sends the results in order with Send, stopping early once the cancelable is canceled. Returns the
+// number of results actually sent, which leaves out results dropped by the cancel, including one that was
+// waiting for room when it happened
+func (gc *goCancelable) SendAll(results []interface{}) int {
+	sent := 0
+	for _, result := range results {
+		select {
+		case <-gc.quit:
+			return sent
+		default:
+		}
+		if gc.sendResult(result) {
+			sent++
+		}
+	}
+	return sent
+}
+
+// SendStream forwards the values of ch with Send until ch is closed, the cancelable is canceled or ctx
+// is done
+func (gc *goCancelable) SendStream(ctx context.Context, ch <-chan interface{}) {
+	for {
+		select {
+		case result, ok := <-ch:
+			if !ok {
+				return
+			}
+			gc.Send(result)
+		case <-gc.quit:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Applies the configured result options to a result before it's sent. Requires locks prior to this
+// method call to remain concurrency-safe.
+func (gc *goCancelable) prepare(result interface{}) interface{} {
+	if gc.sizeOf != nil {
+		if size := gc.sizeOf(result); size > gc.maxSize {
+			result = fmt.Errorf("%w: %d bytes exceeds %d", ErrResultTooLarge, size, gc.maxSize)
+		}
+	}
+	if gc.timestamp {
+		result = Timestamped{At: time.Now(), Value: result}
+	}
+	return result
+}
+
+// Stores a result sent on the channel. Requires locks prior to this method call to remain
+// concurrency-safe.
+func (gc *goCancelable) delivered(result interface{}) {
+	gc.deliveredAll(result, 1)
+}
+
+// Like delivered for a batch or group sent on the channel as one value, counting the n results in it
+func (gc *goCancelable) deliveredAll(result interface{}, n int) {
+	gc.lastResult.Store(&result)
+	gc.deliveries.Add(uint64(n))
+	gc.pushes.Add(1)
+	gc.notifyChanged()
+	if err, ok := result.(error); ok && gc.firstErr == nil {
+		gc.firstErr = err
+	}
+	if gc.record {
+		gc.history = append(gc.history, result)
+	}
+	if gc.replayN > 0 {
+		gc.remember(result)
+	}
+	if gc.extension > 0 {
+		gc.extendDeadline(gc.extension)
 	}
 }
 
 // Receive returns the receive channel
 func (gc *goCancelable) Receive() <-chan interface{} {
+	return gc.receiver()
+}
+
+// Returns the channel for a consumer, recording that there is one for HasReceiver
+func (gc *goCancelable) receiver() chan interface{} {
+	gc.receiving.Store(true)
 	return gc.send
 }
 
-// Start calls the associated gorace handler if the cancelable has not been canceled or started. If the cancelable
-// is canceled or has already started this call does nothing
-func (gc *goCancelable) Start(ctx context.Context) GoCancelable {
+// HasReceiver reports whether a consumer is likely attached. Go can't tell whether a goroutine is blocked
+// receiving on a channel, so this is a heuristic: it returns true once the channel was handed to a consumer
+// through Receive, Next, Subscribe, Drain or the like, until the cancelable is canceled. A consumer that
+// stopped receiving still counts, so a handler shouldn't rely on it to never block. It is meant to pick
+// between a blocking Send and one that can give up, like SendTimeout
+func (gc *goCancelable) HasReceiver() bool {
+	return gc.receiving.Load() && !gc.canceled.Load()
+}
+
+// Next receives the next result, blocking until there is one. Returns false once the channel is closed,
+// after which Err tells whether the results ended because of an error. This mirrors the sql.Rows idiom:
+//
+//	for result, ok := cancelable.Next(); ok; result, ok = cancelable.Next() {
+//		...
+//	}
+//	if err := cancelable.Err(); err != nil {
+//		...
+//	}
+func (gc *goCancelable) Next() (interface{}, bool) {
+	result, ok := <-gc.receiver()
+	return result, ok
+}
+
+// Err returns the cause the cancelable was canceled with, nil if it completed or was canceled without one
+func (gc *goCancelable) Err() error {
+	return gc.Cause()
+}
+
+// WaitWithResult waits for the next result, the channel to close or ctx to be done, whichever comes first
+func (gc *goCancelable) WaitWithResult(ctx context.Context) (interface{}, bool, bool) {
+	select {
+	case result, ok := <-gc.receiver():
+		return result, ok, !ok
+	case <-ctx.Done():
+		return nil, false, false
+	}
+}
+
+// Subscribe starts the cancelable with ctx and calls fn with each received result from a dedicated
+// goroutine. Delivery stops when the channel is closed or ctx is done, the cancelable is not canceled
+// when ctx is done. With WithReplay, fn is first called with the most recently sent results
+func (gc *goCancelable) Subscribe(ctx context.Context, fn func(result interface{})) GoCancelable {
+	gc.mu.Lock()
+	replay := gc.recent()
+	gc.mu.Unlock()
+	gc.Start(ctx)
+	go func() {
+		for _, result := range replay {
+			fn(result)
+		}
+		for {
+			select {
+			case result, ok := <-gc.receiver():
+				if !ok {
+					return
+				}
+				fn(result)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return gc
+}
+
+// Listen registers a subscriber for results routed with SendTo and returns its id along with its channel,
+// which buffers one result like the main channel. Listeners are closed on cancel, one registered after
+// cancel gets a closed channel and an id no result is routed to
+func (gc *goCancelable) Listen() (int, <-chan interface{}) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	listener := make(chan interface{}, 1)
+	if gc.canceled.Load() {
+		close(listener)
+		return -1, listener
+	}
+	if gc.listeners == nil {
+		gc.listeners = make(map[int]chan interface{})
+	}
+	id := len(gc.listeners)
+	gc.listeners[id] = listener
+	return id, listener
+}
+
+// SendTo sends the result to the subscriber registered with id by Listen, waiting for room without holding
+// the lock like Send. Does nothing for unknown ids or once canceled
+func (gc *goCancelable) SendTo(id int, result interface{}) {
+	gc.mu.Lock()
+	listener, ok := gc.listeners[id]
+	gc.mu.Unlock()
+	if !ok {
+		return
+	}
+	// The read lock keeps cancel from closing the listener while the result is in flight
+	gc.closing.RLock()
+	defer gc.closing.RUnlock()
+	if gc.canceled.Load() {
+		return
+	}
+	select {
+	case listener <- result:
+	case <-gc.quit:
+	}
+}
+
+// Pending returns the number of results sent but not yet received
+func (gc *goCancelable) Pending() int {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return len(gc.send)
+}
+
+// Drain reads and discards results on a goroutine until the channel is closed, so a handler blocked on Send
+// gets to run to completion instead of having its results dropped by Cancel
+func (gc *goCancelable) Drain() {
+	go func() {
+		for range gc.receiver() {
+		}
+	}()
+}
+
+// SetHandler replaces the handler as long as the cancelable is idle
+func (gc *goCancelable) SetHandler(handler func(ctx context.Context, cancelable GoCancelable)) error {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.canceled.Load() {
+		return ErrAlreadyCanceled
+	}
+	if gc.started {
+		return ErrAlreadyStarted
+	}
+	gc.handler = handler
+	return nil
+}
+
+// Named sets the name used to identify the cancelable. Does nothing once started or canceled
+func (gc *goCancelable) Named(name string) GoCancelable {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.idle() {
+		gc.name = name
+	}
+	return gc
+}
+
+// Name returns the name set with Named or GoRaceNamed
+func (gc *goCancelable) Name() string {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.name
+}
+
+// Buffered replaces the channel with one buffering n results. Does nothing once started or canceled.
+// Channels returned by Receive before this call are not replaced, so configure the buffer first
+func (gc *goCancelable) Buffered(n int) GoCancelable {
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
-	if !gc.canceled && !gc.started {
-		gc.started = true
-		// Call the handler
-		go func(ctx context.Context, gc *goCancelable) {
-			defer gc.Cancel() // Clean up resources after handler is called
-			gc.handler(ctx, gc)
-		}(ctx, gc)
+	if gc.idle() {
+		gc.send = make(chan interface{}, n)
 	}
 	return gc
 }
 
+// Logged sets the logger lifecycle events and warnings are written to, see WithLogger. Does nothing once
+// started or canceled
+func (gc *goCancelable) Logged(l *slog.Logger) GoCancelable {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.idle() {
+		gc.logger = l
+	}
+	return gc
+}
+
+// Returns true if the cancelable was neither started nor canceled. Requires locks prior to this method
+// call to remain concurrency-safe.
+func (gc *goCancelable) idle() bool {
+	return !gc.started && !gc.canceled.Load()
+}
+
+// Start calls the associated gorace handler if the cancelable has not been canceled or started. If the cancelable
+// is canceled or has already started this call does nothing. A nil ctx stands for the context given to
+// GoRaceWithContext, or the background context
+func (gc *goCancelable) Start(ctx context.Context) GoCancelable {
+	gc.start(ctx)
+	return gc
+}
+
+// StartE is like Start but returns ErrAlreadyCanceled or ErrAlreadyStarted instead of doing nothing
+func (gc *goCancelable) StartE(ctx context.Context) (GoCancelable, error) {
+	return gc, gc.start(ctx)
+}
+
+// MustStart is like StartE but panics with the error instead of returning it, for starts that can only fail
+// because of a programming error
+func (gc *goCancelable) MustStart(ctx context.Context) GoCancelable {
+	if err := gc.start(ctx); err != nil {
+		panic(fmt.Sprintf("gorace: MustStart: %v", err))
+	}
+	return gc
+}
+
+// StartDetached starts the cancelable with a context carrying the values of ctx but not its cancellation
+// or deadline, for work like cleanup that must outlive the caller. Cancel still stops it
+func (gc *goCancelable) StartDetached(ctx context.Context) GoCancelable {
+	return gc.Start(context.WithoutCancel(ctx))
+}
+
+// StartOnce starts the cancelable like Start and returns true if this call launched the handler. Of
+// concurrent calls only one returns true, which saves callers a sync.Once around the first start
+func (gc *goCancelable) StartOnce(ctx context.Context) bool {
+	return gc.start(ctx) == nil
+}
+
+// Calls the associated gorace handler on a goroutine, returns why the handler wasn't called otherwise
+func (gc *goCancelable) start(ctx context.Context) error {
+	ctx, err := gc.begin(ctx)
+	if err != nil {
+		return err
+	}
+	if gc.name == "" {
+		go gc.execute(ctx)
+		return nil
+	}
+	// Label the goroutine with the name so it can be told apart in goroutine profiles and dumps. The name
+	// can't change once started, reading it can't race
+	go pprof.Do(ctx, pprof.Labels("gorace", gc.name), gc.execute)
+	return nil
+}
+
+// Run calls the handler on the calling goroutine and returns the last result once it returned. The handler
+// isn't called if the cancelable is canceled or started. Nothing receives results while the handler runs,
+// so Send blocks forever once the buffer is full: size it with Buffered for every result sent
+func (gc *goCancelable) Run(ctx context.Context) interface{} {
+	if ctx, err := gc.begin(ctx); err == nil {
+		gc.execute(ctx)
+	}
+	return gc.LastResult()
+}
+
+// Marks the cancelable started and returns the context for the handler, or why the handler can't be
+// called. An idle cancelable started with a done context is canceled with the context's cause instead
+func (gc *goCancelable) begin(ctx context.Context) (context.Context, error) {
+	if ctx == nil {
+		ctx = gc.defaultCtx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+	}
+	gc.mu.Lock()
+	if ctx.Err() == nil || gc.canceled.Load() || gc.started {
+		defer gc.mu.Unlock()
+		return gc.launch(ctx)
+	}
+	cause := context.Cause(ctx)
+	gc.cancel(cause)
+	gc.mu.Unlock()
+	gc.propagate(cause)
+	return nil, ctx.Err()
+}
+
+// Marks the cancelable started and arms its timeout and sink. Requires locks prior to this method call to
+// remain concurrency-safe.
+func (gc *goCancelable) launch(ctx context.Context) (context.Context, error) {
+	if gc.canceled.Load() {
+		return nil, ErrAlreadyCanceled
+	}
+	if gc.started {
+		return nil, ErrAlreadyStarted
+	}
+	if gc.handler == nil {
+		return nil, ErrNoHandler
+	}
+	gc.started = true
+	gc.startedAt = time.Now()
+	gc.ctx = ctx
+	// The handler gets a context of its own so canceling the cancelable, from any source, cancels it with
+	// the same cause
+	ctx, gc.stop = context.WithCancelCause(ctx)
+	if gc.timeout > 0 {
+		gc.deadline = time.Now().Add(gc.timeout)
+		gc.timer = time.AfterFunc(gc.timeout, func() {
+			gc.CancelCause(context.DeadlineExceeded)
+		})
+	}
+	if gc.sink != nil {
+		go gc.consume()
+	}
+	return ctx, nil
+}
+
+// Calls the handler and cleans up once it returned
+func (gc *goCancelable) execute(ctx context.Context) {
+	defer close(gc.done)
+	defer gc.finish() // Clean up resources after handler is called
+	defer gc.recoverPanic()
+	gc.logEvent(slog.LevelDebug, "gorace: started")
+	if gc.observer != nil {
+		gc.observer.Started(gc)
+	}
+	gc.run(ctx)
+}
+
+// Recovers a handler panic and cancels the cancelable with it as a *PanicError
+func (gc *goCancelable) recoverPanic() {
+	if v := recover(); v != nil {
+		gc.mu.Lock()
+		name := gc.name
+		gc.mu.Unlock()
+		stack := debug.Stack()
+		gc.logEvent(slog.LevelError, "gorace: handler panicked", slog.Any("panic", v), slog.String("stack", string(stack)))
+		gc.CancelCause(&PanicError{Name: name, Value: v, Stack: stack})
+	}
+}
+
+// Feeds each result to the sink until the channel closes. A sink error cancels the cancelable, the
+// remaining results are discarded so a blocked Send can't hold up the cancel
+func (gc *goCancelable) consume() {
+	for result := range gc.receiver() {
+		if err := gc.sink(result); err != nil {
+			go gc.CancelCause(err)
+			for range gc.send {
+			}
+			return
+		}
+	}
+}
+
 // StartBackground calls Start on a goroutine with the specified context
 func (gc *goCancelable) StartBackground(ctx context.Context) GoCancelable {
 	go gc.Start(ctx)
 	return gc
 }
 
+// Replay sends the recorded results on dst in the order they were delivered. This blocks until dst
+// accepts every result and does not close dst
+func (gc *goCancelable) Replay(dst chan<- interface{}) {
+	for _, result := range gc.History() {
+		dst <- result
+	}
+}
+
+// History returns a copy of the recorded results in the order they were delivered, or nil if results
+// aren't recorded
+func (gc *goCancelable) History() []interface{} {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.history == nil {
+		return nil
+	}
+	history := make([]interface{}, len(gc.history))
+	copy(history, gc.history)
+	return history
+}
+
+// Deadline returns the earliest of the timeout deadline and the handler context deadline. Both are only
+// known once the cancelable is started
+func (gc *goCancelable) Deadline() (time.Time, bool) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	deadline, ok := gc.deadline, !gc.deadline.IsZero()
+	if gc.ctx != nil {
+		if ctxDeadline, ctxOk := gc.ctx.Deadline(); ctxOk && (!ok || ctxDeadline.Before(deadline)) {
+			deadline, ok = ctxDeadline, true
+		}
+	}
+	return deadline, ok
+}
+
+// StartedAt returns the time Start launched the handler
+func (gc *goCancelable) StartedAt() (time.Time, bool) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.startedAt, gc.started
+}
+
+// Elapsed returns the time since the handler was launched, up to the cancel once canceled. Returns 0 if
+// the cancelable wasn't started
+func (gc *goCancelable) Elapsed() time.Duration {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if !gc.started {
+		return 0
+	}
+	if gc.canceled.Load() {
+		return gc.stoppedAt.Sub(gc.startedAt)
+	}
+	return time.Since(gc.startedAt)
+}
+
+// NoDeadline is returned by Remaining for cancelables without a deadline
+const NoDeadline = time.Duration(math.MaxInt64)
+
+// Remaining returns the time left before the deadline reported by Deadline, or NoDeadline if there is none,
+// so a handler holding only the cancelable can tell whether there's time for another step. Returns 0 once
+// the deadline passed
+func (gc *goCancelable) Remaining() time.Duration {
+	deadline, ok := gc.Deadline()
+	if !ok {
+		return NoDeadline
+	}
+	if remaining := time.Until(deadline); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// ExtendDeadline reschedules the timeout to d from now, letting a handler that's close to done earn more
+// time. Does nothing if the cancelable has no running timeout or is canceled
+func (gc *goCancelable) ExtendDeadline(d time.Duration) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.extendDeadline(d)
+}
+
+// Reschedules the timeout to d from now. Requires locks prior to this method call to remain
+// concurrency-safe.
+func (gc *goCancelable) extendDeadline(d time.Duration) {
+	if gc.timer == nil || gc.canceled.Load() {
+		return
+	}
+	if gc.timer.Reset(d) {
+		gc.deadline = time.Now().Add(d)
+	}
+}
+
 // LastResult returns the last successful result sent on the cancelable's channel. This does not return
-// values attempted to be sent after the cancelable is canceled
+// values attempted to be sent after the cancelable is canceled. The result is stored as soon as it's handed
+// to the channel, independently of consumers, so it reflects the last value produced rather than the last
+// one consumed. That makes it suitable for monitoring progress without taking results away from the main
+// consumer. Consumers receive straight from the channel, so the last consumed value isn't tracked
 func (gc *goCancelable) LastResult() interface{} {
+	return gc.last()
+}
+
+// Returns the last result sent on the channel
+func (gc *goCancelable) last() interface{} {
+	if result := gc.lastResult.Load(); result != nil {
+		return *result
+	}
+	return nil
+}
+
+// LastResultChanged returns a channel that is closed the next time a result is sent, so LastResult can be
+// waited on instead of polled. Each change needs a fresh call since the channel is replaced once closed.
+// The channel is closed on cancel, and the one returned after cancel is already closed
+func (gc *goCancelable) LastResultChanged() <-chan struct{} {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.changed == nil {
+		gc.changed = make(chan struct{})
+		if gc.canceled.Load() {
+			close(gc.changed)
+		} else {
+			gc.watched.Store(true)
+		}
+	}
+	return gc.changed
+}
+
+// FirstError returns the first error-typed result that was sent, including errors routed to the error
+// channel, or nil
+func (gc *goCancelable) FirstError() error {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.firstErr
+}
+
+// SetResult stores value under key for consumers to read with Results, for handlers producing several
+// distinct outputs rather than a stream. Setting a key again replaces its value
+func (gc *goCancelable) SetResult(key string, value interface{}) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.named == nil {
+		gc.named = make(map[string]interface{})
+	}
+	gc.named[key] = value
+}
+
+// Results returns a copy of the results stored with SetResult
+func (gc *goCancelable) Results() map[string]interface{} {
 	gc.mu.Lock()
 	defer gc.mu.Unlock()
-	return gc.lastResult
+	results := make(map[string]interface{}, len(gc.named))
+	for key, value := range gc.named {
+		results[key] = value
+	}
+	return results
+}
+
+// LatestProduced returns the result of the most recent Send, same as LastResult. It is updated as soon as
+// the result is handed to the channel, independently of consumers, which makes it suitable for monitoring
+// progress without taking results away from the main consumer
+func (gc *goCancelable) LatestProduced() interface{} {
+	return gc.last()
+}
+
+// ID returns the unique identifier of the cancelable
+func (gc *goCancelable) ID() uint64 {
+	return gc.id
+}
+
+// SourceID returns the ID set with WithSourceID, or the cancelable's own ID
+func (gc *goCancelable) SourceID() uint64 {
+	if gc.sourceID != 0 {
+		return gc.sourceID
+	}
+	return gc.id
+}
+
+// Equal compares the cancelables by source ID, so a wrapped cancelable equals the cancelable it wraps and
+// cancelables derived from the same source equal each other. Compare ID for strict identity
+func (gc *goCancelable) Equal(other GoCancelable) bool {
+	return other != nil && other.SourceID() == gc.SourceID()
 }
@@ -2,7 +2,13 @@ package gorace
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -149,10 +155,1028 @@ func (suite *GoRaceTestSuite) TestGoRaceCheckMultipleStarts() {
 	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
 }
 
+func (suite *GoRaceTestSuite) TestGoRaceSendTimeout() {
+	sent := make(chan bool)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		sent <- cancelable.SendTimeout(false, 50*time.Millisecond)
+		sent <- cancelable.LastResult() == true
+		sent <- cancelable.SendTimeout(false, time.Second)
+	})
+	cancelable.Start(context.Background())
+
+	suite.Equal(false, <-sent, "cancelable.SendTimeout() should time out on a full channel")
+	suite.Equal(true, <-sent, "cancelable.LastResult() should not be updated on timeout")
+	suite.Equal(true, <-cancelable.Receive(), "<-cancelable.Receive() failed")
+	suite.Equal(true, <-sent, "cancelable.SendTimeout() should succeed once the channel is drained")
+	suite.Equal(false, <-cancelable.Receive(), "<-cancelable.Receive() failed")
+	suite.Equal(false, cancelable.LastResult(), "cancelable.LastResult() should be the last delivered result")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceHasReceiver() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	})
+	cancelable.Start(context.Background())
+	suite.False(cancelable.HasReceiver(), "nothing should receive before the channel is handed out")
+
+	cancelable.Receive()
+	suite.True(cancelable.HasReceiver(), "calling Receive should count as a receiver")
+
+	cancelable.Cancel()
+	suite.False(cancelable.HasReceiver(), "a canceled cancelable should have no receiver")
+
+	typed := GoRaceInt(func(ctx context.Context, cancelable GoCancelable) {
+		suite.Eventually(cancelable.HasReceiver, time.Second, time.Millisecond, "a typed consumer should count as a receiver")
+	})
+	typed.Start(context.Background())
+	for range typed.Receive() {
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendAndWait() {
+	consumed := make(chan bool)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		consumed <- cancelable.SendAndWait(1)
+		consumed <- cancelable.SendAndWait(2)
+	})
+	cancelable.Start(context.Background())
+
+	select {
+	case <-consumed:
+		suite.Fail("cancelable.SendAndWait() should wait until the result is received")
+	case <-time.After(20 * time.Millisecond):
+	}
+	suite.Equal(1, <-cancelable.Receive())
+	suite.Equal(true, <-consumed, "cancelable.SendAndWait() should report the result as received")
+
+	cancelable.Cancel()
+	suite.Equal(false, <-consumed, "cancelable.SendAndWait() should give up on cancel")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendAndWaitOwnResult() {
+	consumed := make(chan bool)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		consumed <- cancelable.SendAndWait(1)
+		cancelable.Send(2)
+		<-ctx.Done()
+	}, WithBuffer(2))
+	cancelable.Start(context.Background())
+	defer cancelable.Cancel()
+
+	suite.Equal(1, <-cancelable.Receive())
+	suite.Equal(true, <-consumed, "cancelable.SendAndWait() should return once its own result is received")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendAndWaitOptions() {
+	consumed := make(chan bool, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		consumed <- cancelable.SendAndWait(nil)
+	}, WithCancelOnNil())
+	cancelable.Start(context.Background())
+
+	suite.Equal(false, <-consumed, "cancelable.SendAndWait() should apply the send options")
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.SendAndWait(nil) should cancel with WithCancelOnNil")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceDeriveCancelsChildren() {
+	parent := rapidSendCancelable()
+	child := parent.Derive(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+
+	parent.Cancel()
+
+	suite.Equal(true, child.IsCanceled(), "child.IsCanceled() should be true")
+	suite.Nil(child.Cause(), "child.Cause() should be nil")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceContextCause() {
+	failure := errors.New("quota exceeded")
+	causes := make(chan error, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+	})
+	cancelable.Start(context.Background())
+	cancelable.CancelCause(failure)
+	suite.Equal(failure, <-causes, "context.Cause(ctx) should be the cancelable's cause")
+
+	cancelable = GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+	})
+	cancelable.Start(context.Background())
+	cancelable.Cancel()
+	suite.Equal(context.Canceled, <-causes, "a plain cancel should leave the default cause")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceDeriveCascadeUp() {
+	failure := errors.New("subtask failed")
+	parent := rapidSendCancelable()
+	child := parent.Derive(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.CancelCause(failure)
+	}, WithCascadeUp())
+	sibling := parent.Derive(func(ctx context.Context, cancelable GoCancelable) {})
+
+	child.Start(context.Background())
+	for range child.Receive() {
+	}
+
+	suite.Equal(true, parent.IsCanceled(), "parent.IsCanceled() should be true")
+	suite.Equal(failure, parent.Cause(), "parent.Cause() should be the child's cause")
+	suite.Equal(true, sibling.IsCanceled(), "sibling.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceDeriveNoCascadeUp() {
+	parent := rapidSendCancelable()
+	child := parent.Derive(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.CancelCause(errors.New("subtask failed"))
+	})
+
+	child.Start(context.Background())
+	for range child.Receive() {
+	}
+
+	suite.Equal(false, parent.IsCanceled(), "parent.IsCanceled() should be false")
+	parent.Cancel()
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceAddChild() {
+	parent, child, grandchild := New(), New(), New()
+	parent.AddChild(child)
+	parent.AddChild(child)
+	child.AddChild(grandchild)
+	grandchild.AddChild(parent) // cycle
+	parent.AddChild(parent)
+
+	suite.Equal(true, parent.Cancel(), "parent.Cancel() should be true")
+	suite.Equal(true, child.IsCanceled(), "child.IsCanceled() should be true")
+	suite.Equal(true, grandchild.IsCanceled(), "grandchild.IsCanceled() should be true")
+
+	late := New()
+	parent.AddChild(late)
+	suite.Equal(true, late.IsCanceled(), "children added after cancel should be canceled right away")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceDrain() {
+	returned := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		defer close(returned)
+		for i := 0; i < 50; i++ {
+			cancelable.Send(i)
+		}
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelable.Start(ctx)
+
+	cancelable.Drain()
+	suite.Equal(true, cancelable.Cancel(), "cancelable.Cancel() should cancel")
+	cancel()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		suite.Fail("handler should not stay blocked after Drain and Cancel")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRacePending() {
+	sent := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		close(sent)
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelable.Start(ctx)
+	<-sent
+
+	suite.Equal(1, cancelable.Pending(), "cancelable.Pending() should count the buffered result")
+	<-cancelable.Receive()
+	suite.Equal(0, cancelable.Pending(), "cancelable.Pending() should be 0 once received")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceConcurrentSendCancelStress() {
+	for run := 0; run < 10; run++ {
+		cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			var wg sync.WaitGroup
+			for i := 0; i < 1000; i++ {
+				wg.Add(2)
+				go func(i int) {
+					defer wg.Done()
+					cancelable.Send(i)
+				}(i)
+				go func() {
+					defer wg.Done()
+					cancelable.Cancel()
+				}()
+			}
+			wg.Wait()
+		})
+		cancelable.Start(context.Background())
+
+		for range cancelable.Receive() {
+		}
+
+		suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSubscribe() {
+	results := make(chan interface{}, 5)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 5; i++ {
+			cancelable.Send(i)
+		}
+	}).Subscribe(context.Background(), func(result interface{}) {
+		results <- result
+	})
+
+	for i := 0; i < 5; i++ {
+		select {
+		case result := <-results:
+			suite.Equal(i, result, "fn should be called for each result in order")
+		case <-time.After(time.Second):
+			suite.Fail("fn should be called for each result")
+		}
+	}
+	suite.Eventually(cancelable.IsCanceled, time.Second, 10*time.Millisecond, "cancelable.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendTo() {
+	cancelable := New()
+	first, firstResults := cancelable.Listen()
+	second, secondResults := cancelable.Listen()
+	suite.NotEqual(first, second, "listeners should have distinct ids")
+	suite.NoError(cancelable.SetHandler(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.SendTo(first, "first")
+		cancelable.SendTo(second, "second")
+		cancelable.SendTo(42, "nobody")
+	}))
+	cancelable.Start(context.Background())
+
+	suite.Equal("first", <-firstResults)
+	suite.Equal("second", <-secondResults)
+	for range cancelable.Receive() {
+	}
+	_, ok := <-firstResults
+	suite.False(ok, "listeners should be closed on cancel")
+	_, ok = <-secondResults
+	suite.False(ok, "listeners should be closed on cancel")
+	_, late := cancelable.Listen()
+	_, ok = <-late
+	suite.False(ok, "listeners registered after cancel should be closed")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStartE() {
+	cancelable := rapidSendCancelable()
+
+	_, err := cancelable.StartE(context.Background())
+	suite.NoError(err, "the first start should call the handler")
+	_, err = cancelable.StartE(context.Background())
+	suite.ErrorIs(err, ErrAlreadyStarted)
+
+	for range cancelable.Receive() {
+	}
+
+	canceled := rapidSendCancelable()
+	canceled.Cancel()
+	_, err = canceled.StartE(context.Background())
+	suite.ErrorIs(err, ErrAlreadyCanceled)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMustStart() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+
+	suite.NotPanics(func() { cancelable.MustStart(context.Background()) })
+	suite.PanicsWithValue("gorace: MustStart: "+ErrAlreadyStarted.Error(), func() {
+		cancelable.MustStart(context.Background())
+	})
+	suite.Equal(true, <-cancelable.Receive())
+	cancelable.Cancel()
+	suite.Panics(func() { cancelable.MustStart(context.Background()) }, "starting a canceled cancelable should panic")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSetHandler() {
+	cancelable := New()
+
+	_, err := cancelable.StartE(context.Background())
+	suite.ErrorIs(err, ErrNoHandler)
+
+	suite.NoError(cancelable.SetHandler(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	}))
+	cancelable.Start(context.Background())
+	suite.ErrorIs(cancelable.SetHandler(func(ctx context.Context, cancelable GoCancelable) {}), ErrAlreadyStarted)
+
+	suite.Equal(true, <-cancelable.Receive())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendAll() {
+	sent := make(chan int, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		sent <- cancelable.SendAll([]interface{}{0, 1, 2})
+	})
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2}, results)
+	suite.Equal(3, <-sent)
+
+	canceled := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		sent <- cancelable.SendAll([]interface{}{0, 1, nil, 3, 4})
+	}, WithCancelOnNil())
+	canceled.Start(context.Background())
+	for range canceled.Receive() {
+	}
+	suite.Equal(2, <-sent, "SendAll should stop once canceled and count only the sent results")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendAllCancelWhileBlocked() {
+	sent := make(chan int, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		sent <- cancelable.SendAll([]interface{}{0, 1, 2})
+	})
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool { return cancelable.Pending() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // gives the second send time to block
+
+	cancelable.Cancel()
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{0}, results)
+	suite.Equal(len(results), <-sent, "the result dropped by the cancel should not be counted")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendStream() {
+	source := make(chan interface{}, 3)
+	for i := 0; i < 3; i++ {
+		source <- i
+	}
+	close(source)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.SendStream(ctx, source)
+	})
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2}, results, "every value of the source should be sent")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendStreamStopsOnCancel() {
+	returned := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		defer close(returned)
+		cancelable.SendStream(ctx, make(chan interface{}))
+	})
+	cancelable.Start(context.Background())
+
+	cancelable.Cancel()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		suite.Fail("cancelable.SendStream() should stop on cancel")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStartCanceledContext() {
+	called := false
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		called = true
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelable.StartBackground(ctx)
+	for range cancelable.Receive() {
+	}
+
+	suite.False(called, "the handler should not be called with a done context")
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+	suite.Equal(context.Canceled, cancelable.Cause(), "cancelable.Cause() should be the context's cause")
+	_, err := GoRace(func(ctx context.Context, cancelable GoCancelable) {}).StartE(ctx)
+	suite.ErrorIs(err, context.Canceled)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceFluentConfiguration() {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 4; i++ {
+			cancelable.Send(i)
+		}
+	}).Named("fetch").Buffered(4).Logged(logger)
+
+	cancelable.Start(context.Background())
+	cancelable.Named("ignored").Buffered(1)
+
+	gc := cancelable.(*goCancelable)
+	suite.Equal("fetch", gc.name, "Named() should set the name while idle")
+	suite.Equal(logger, gc.logger, "Logged() should set the logger while idle")
+	suite.Equal(4, cap(cancelable.Receive()), "Buffered() should set the buffer while idle")
+	for range cancelable.Receive() {
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithContext() {
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request"))
+	defer cancel()
+	cancelable := GoRaceWithContext(ctx, func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(ctx.Value(key{}))
+	})
+	cancelable.Start(nil)
+
+	suite.Equal("request", <-cancelable.Receive(), "Start(nil) should use the construction context")
+
+	expiring, stop := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stop()
+	idle := GoRaceWithContext(expiring, func(ctx context.Context, cancelable GoCancelable) {})
+	suite.Eventually(idle.IsCanceled, time.Second, time.Millisecond, "the deadline should cancel an idle cancelable")
+	suite.Equal(context.DeadlineExceeded, idle.Cause())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceChan() {
+	queue := make(chan interface{}, 3)
+	cancelable := GoRaceChan(queue, func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	})
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range queue {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2}, results, "results should be sent on the supplied channel")
+	suite.Equal(true, cancelable.IsCanceled(), "the supplied channel should be closed by the cancel")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceNamed() {
+	cancelable := GoRaceNamed("fetch", func(ctx context.Context, cancelable GoCancelable) {})
+
+	suite.Equal("fetch", cancelable.Name())
+	suite.Equal("", New().Name(), "unnamed cancelables should have an empty name")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceNamedProfilerLabel() {
+	cancelable := GoRaceNamed("fetch", func(ctx context.Context, cancelable GoCancelable) {
+		label, _ := pprof.Label(ctx, "gorace")
+		cancelable.Send(label)
+	})
+	cancelable.Start(context.Background())
+
+	suite.Equal("fetch", <-cancelable.Receive(), "the handler goroutine should be labeled with the name")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceLastResultBeforeReceive() {
+	sent := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		close(sent)
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelable.Start(ctx)
+	<-sent
+
+	suite.Equal(1, cancelable.LastResult(), "cancelable.LastResult() should not wait for consumers")
+	suite.Equal(1, cancelable.LatestProduced(), "cancelable.LatestProduced() should match cancelable.LastResult()")
+	suite.Equal(1, cancelable.Pending(), "the result should not be consumed by LastResult()")
+	suite.Equal(1, <-cancelable.Receive())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWaitWithResult() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	cancelable.Start(context.Background())
+
+	value, received, canceled := cancelable.WaitWithResult(context.Background())
+	suite.Equal(true, value)
+	suite.True(received, "received should be true for a value")
+	suite.False(canceled, "canceled should be false for a value")
+
+	value, received, canceled = cancelable.WaitWithResult(context.Background())
+	suite.Nil(value)
+	suite.False(received, "received should be false once closed")
+	suite.True(canceled, "canceled should be true once closed")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWaitWithResultContext() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelable.Start(ctx)
+
+	wait, stop := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stop()
+	value, received, canceled := cancelable.WaitWithResult(wait)
+	suite.Nil(value)
+	suite.False(received, "received should be false when ctx is done")
+	suite.False(canceled, "canceled should be false when ctx is done")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceShutdown() {
+	returned := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		defer close(returned)
+		for i := 0; i < 50; i++ {
+			cancelable.Send(i)
+		}
+		<-time.After(50 * time.Millisecond)
+	})
+	cancelable.Start(context.Background())
+
+	suite.NoError(cancelable.Shutdown(context.Background()))
+	select {
+	case <-returned:
+	default:
+		suite.Fail("cancelable.Shutdown() should wait for the handler to return")
+	}
+	suite.NoError(New().Shutdown(context.Background()), "idle cancelables should shut down right away")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceShutdownTimeout() {
+	stop := make(chan struct{})
+	defer close(stop)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-stop
+	})
+	cancelable.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	suite.ErrorIs(cancelable.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceFirstError() {
+	first, second := errors.New("first"), errors.New("second")
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		cancelable.Send(first)
+		cancelable.Send(second)
+	})
+	cancelable.Start(context.Background())
+
+	for range cancelable.Receive() {
+	}
+
+	suite.Equal(first, cancelable.FirstError(), "cancelable.FirstError() should be the first error sent")
+	suite.Equal(second, cancelable.LastResult())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCancelDrain() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send("last")
+		<-ctx.Done()
+	})
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool { return cancelable.Pending() == 1 }, time.Second, time.Millisecond)
+
+	result, ok := cancelable.CancelDrain()
+
+	suite.True(ok, "the buffered result should be recovered")
+	suite.Equal("last", result)
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+	_, ok = <-cancelable.Receive()
+	suite.False(ok, "the recovered result should not be received again")
+	_, ok = cancelable.CancelDrain()
+	suite.False(ok, "an already canceled cancelable has nothing to recover")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCloseSend() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+		cancelable.CloseSend()
+		cancelable.Send(3)
+	}).Buffered(3)
+	cancelable.Start(context.Background())
+	<-cancelable.(*goCancelable).done
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2}, results, "buffered results should be received after CloseSend")
+	suite.NoError(cancelable.Cause(), "cancelable.Cause() should be nil after CloseSend")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCloseSendKeepsContext() {
+	closed := make(chan error)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.CloseSend()
+		closed <- ctx.Err()
+		<-ctx.Done()
+		closed <- context.Cause(ctx)
+	})
+	cancelable.Start(context.Background())
+
+	suite.NoError(<-closed, "CloseSend should not cancel the handler's context")
+	suite.Equal(1, <-cancelable.Receive())
+	_, ok := <-cancelable.Receive()
+	suite.False(ok, "the channel should be closed after CloseSend")
+
+	failure := errors.New("teardown")
+	cancelable.CancelCause(failure)
+	suite.ErrorIs(<-closed, failure, "canceling after CloseSend should still cancel the handler's context")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceID() {
+	first, second := New(), New()
+	mapped := first.Map(func(result interface{}) interface{} { return result })
+	filtered := mapped.Filter(func(result interface{}) bool { return true })
+
+	suite.NotEqual(first.ID(), second.ID(), "cancelables should have distinct IDs")
+	suite.True(first.Equal(first))
+	suite.False(first.Equal(second), "distinct cancelables should not be equal")
+	suite.NotEqual(first.ID(), mapped.ID(), "a mapped cancelable is a distinct cancelable")
+	suite.True(mapped.Equal(first), "a mapped cancelable should equal its source")
+	suite.True(first.Equal(filtered), "equality should hold through several operators")
+	suite.False(second.Equal(mapped), "a mapped cancelable should not equal other cancelables")
+	checked := GoRaceWithLeakCheck(func(ctx context.Context, cancelable GoCancelable) {})
+	suite.True(checked.Equal(checked.Map(func(result interface{}) interface{} { return result })), "a wrapped handle should equal its derived cancelables")
+	suite.Equal(first.ID(), filtered.SourceID(), "filtered.SourceID() should be the ID of the original source")
+	suite.Equal(second.ID(), second.SourceID(), "second.SourceID() should be its own ID")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStartOnce() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+
+	var wg sync.WaitGroup
+	var launched atomic.Int32
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if cancelable.StartOnce(context.Background()) {
+				launched.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.Equal(int32(1), launched.Load(), "only one StartOnce call should launch the handler")
+	suite.Equal(true, <-cancelable.Receive())
+	cancelable.Cancel()
+	suite.False(cancelable.StartOnce(context.Background()), "a canceled cancelable should not start")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStartDetached() {
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		result := work(ctx)
+		cancelable.Send(ctx.Value(key{}))
+		cancelable.Send(ctx.Err() == nil && result)
+	})
+	cancelable.StartDetached(ctx)
+
+	cancel()
+
+	suite.Equal("value", <-cancelable.Receive(), "the handler context should keep the parent's values")
+	suite.Equal(true, <-cancelable.Receive(), "the handler should run to completion")
+	suite.Nil(cancelable.Cause(), "cancelable.Cause() should be nil")
+
+	stopped := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	}).StartDetached(context.Background())
+	suite.Equal(true, stopped.Cancel(), "a detached cancelable should still be cancelable")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCancelCancelsHandlerContext() {
+	aborted := make(chan error, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		select {
+		case <-ctx.Done():
+			aborted <- ctx.Err()
+		case <-time.After(time.Second):
+			aborted <- nil
+		}
+	})
+	cancelable.Start(context.Background())
+
+	cancelable.Cancel()
+
+	suite.ErrorIs(<-aborted, context.Canceled, "the handler context should be canceled by Cancel")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceReason() {
+	completed := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	suite.Equal(ReasonNone, completed.Reason())
+	completed.Start(context.Background())
+	for range completed.Receive() {
+	}
+	suite.Equal(ReasonCompleted, completed.Reason(), "a handler returning should complete the cancelable")
+
+	canceled := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	canceled.Start(context.Background())
+	canceled.Cancel()
+	for range canceled.Receive() {
+	}
+	suite.Equal(ReasonCanceled, canceled.Reason(), "Cancel should cancel the cancelable")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceElapsed() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	_, ok := cancelable.StartedAt()
+	suite.False(ok, "cancelable.StartedAt() should not be ok before Start")
+	suite.Equal(time.Duration(0), cancelable.Elapsed())
+
+	before := time.Now()
+	cancelable.Start(context.Background())
+	startedAt, ok := cancelable.StartedAt()
+	suite.True(ok, "cancelable.StartedAt() should be ok after Start")
+	suite.False(startedAt.Before(before), "cancelable.StartedAt() should be the start time")
+
+	for range cancelable.Receive() {
+	}
+	elapsed := cancelable.Elapsed()
+	suite.GreaterOrEqual(elapsed, 250*time.Millisecond, "cancelable.Elapsed() should cover the handler run")
+	<-time.After(10 * time.Millisecond)
+	suite.Equal(elapsed, cancelable.Elapsed(), "cancelable.Elapsed() should stop at the cancel")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRun() {
+	calls := 0
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		calls++
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	}).Buffered(3)
+
+	suite.Equal(2, cancelable.Run(context.Background()), "Run should return the last result")
+	suite.Equal(ReasonCompleted, cancelable.Reason())
+	cancelable.Run(context.Background())
+	suite.Equal(1, calls, "Run should not call the handler twice")
+
+	canceled := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Cancel()
+		cancelable.Send(2)
+	})
+	suite.Equal(1, canceled.Run(context.Background()), "Cancel should be honored from within the handler")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceOnCancelExactlyOnce() {
+	var canceled, completed atomic.Int32
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	})
+	cancelable.OnCancel(func() { canceled.Add(1) })
+	cancelable.OnComplete(func() { completed.Add(1) })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelable.Start(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cancelable.Cancel()
+		}()
+	}
+	cancel()
+	wg.Wait()
+	cancelable.Shutdown(context.Background())
+
+	suite.Equal(int32(1), canceled.Load(), "OnCancel callbacks should fire exactly once")
+	suite.Equal(int32(0), completed.Load(), "OnComplete callbacks should not fire on cancel")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceOnComplete() {
+	var canceled, completed atomic.Int32
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	cancelable.OnCancel(func() { canceled.Add(1) })
+	cancelable.OnComplete(func() { completed.Add(1) })
+	cancelable.Start(context.Background())
+
+	for range cancelable.Receive() {
+	}
+	cancelable.Shutdown(context.Background())
+	cancelable.OnComplete(func() { completed.Add(1) })
+
+	suite.Equal(int32(1), canceled.Load(), "OnCancel callbacks should fire exactly once")
+	suite.Equal(int32(2), completed.Load(), "OnComplete callbacks should fire once each, right away once completed")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSetResult() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.SetResult("status", 200)
+		cancelable.SetResult("payload", "body")
+		cancelable.SetResult("status", 204)
+	})
+	cancelable.Start(context.Background())
+
+	for range cancelable.Receive() {
+	}
+	results := cancelable.Results()
+	results["status"] = 500
+
+	suite.Equal(map[string]interface{}{"status": 204, "payload": "body"}, cancelable.Results())
+	suite.Empty(New().Results(), "cancelables without named results should return an empty map")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceNext() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	})
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result, ok := cancelable.Next(); ok; result, ok = cancelable.Next() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2}, results)
+	suite.NoError(cancelable.Err())
+
+	failure := errors.New("request failed")
+	failed := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.CancelCause(failure)
+	})
+	failed.Start(context.Background())
+	_, ok := failed.Next()
+	suite.False(ok, "failed.Next() should be false")
+	suite.Equal(failure, failed.Err())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceLastResultChanged() {
+	next := make(chan int)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := range next {
+			cancelable.Send(i)
+		}
+	}).Buffered(10)
+	cancelable.Start(context.Background())
+
+	for i := 0; i < 3; i++ {
+		changed := cancelable.LastResultChanged()
+		select {
+		case <-changed:
+			suite.Fail("the channel should not be closed before a result is sent")
+		default:
+		}
+		next <- i
+		select {
+		case <-changed:
+			suite.Equal(i, cancelable.LastResult())
+		case <-time.After(time.Second):
+			suite.Fail("the channel should be closed once a result is sent")
+		}
+	}
+
+	changed := cancelable.LastResultChanged()
+	cancelable.Cancel()
+	close(next)
+	_, open := <-changed
+	suite.False(open, "the channel should be closed on cancel")
+	_, open = <-cancelable.LastResultChanged()
+	suite.False(open, "the channel should be closed after cancel")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceCompletedNormally() {
+	completed := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+	}).Start(context.Background())
+	panicked := GoRaceNamed("crash", func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		panic("boom")
+	}).Start(context.Background())
+	canceled := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	}).Start(context.Background())
+	canceled.Cancel()
+
+	for _, cancelable := range []GoCancelable{completed, panicked, canceled} {
+		cancelable.Drain()
+		<-cancelable.Done()
+	}
+
+	suite.True(completed.CompletedNormally(), "a returning handler should complete normally")
+	suite.NoError(completed.Err())
+	suite.False(panicked.CompletedNormally(), "a panicking handler should not complete normally")
+	var panicErr *PanicError
+	suite.ErrorAs(panicked.Err(), &panicErr)
+	suite.Equal("boom", panicErr.Value)
+	suite.Contains(panicErr.Error(), `"crash"`, "the error should name the cancelable")
+	suite.NotEmpty(panicErr.Stack)
+	suite.False(canceled.CompletedNormally(), "a canceled handler should not complete normally")
+}
+
 func TestGoRaceSuite(t *testing.T) {
 	suite.Run(t, new(GoRaceTestSuite))
 }
 
+// Each byte fires one cancel source: Cancel, the timeout, the parent context or the handler returning. Bit
+// 2 fires it from its own goroutine so sources race instead of running in order
+func FuzzOnCancel(f *testing.F) {
+	f.Add([]byte{0})
+	f.Add([]byte{1})
+	f.Add([]byte{2})
+	f.Add([]byte{3})
+	f.Add([]byte{0, 1, 2, 3})
+	f.Add([]byte{3, 2, 1, 0})
+	f.Add([]byte{4, 5, 6, 7})
+	f.Add([]byte{6, 0, 7, 2, 4})
+	f.Fuzz(func(t *testing.T, sources []byte) {
+		var opts []Option
+		for _, source := range sources {
+			if source%4 == 1 {
+				opts = []Option{WithTimeout(time.Millisecond)}
+			}
+		}
+		returned := make(chan struct{})
+		var returnOnce sync.Once
+		cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			select {
+			case <-ctx.Done():
+			case <-returned:
+			}
+		}, opts...)
+		var canceled atomic.Int32
+		cancelable.OnCancel(func() { canceled.Add(1) })
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelable.StartBackground(ctx)
+
+		var wg sync.WaitGroup
+		fire := func(source byte) {
+			switch source % 4 {
+			case 0:
+				cancelable.Cancel()
+			case 1:
+				time.Sleep(time.Millisecond)
+			case 2:
+				cancel()
+			case 3:
+				returnOnce.Do(func() { close(returned) })
+			}
+		}
+		for _, source := range sources {
+			if source&4 != 0 {
+				wg.Add(1)
+				go func(source byte) {
+					defer wg.Done()
+					fire(source)
+				}(source)
+				continue
+			}
+			fire(source)
+		}
+		wg.Wait()
+		cancelable.Cancel() // in case no source fired
+		<-cancelable.Done()
+
+		deadline := time.Now().Add(time.Second)
+		for canceled.Load() == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if n := canceled.Load(); n != 1 {
+			t.Fatalf("OnCancel callback ran %d times for sources %v, want exactly once", n, sources)
+		}
+	})
+}
+
 func work(ctx context.Context) bool {
 	<-time.After(250 * time.Millisecond)
 	return true
@@ -168,3 +1192,46 @@ func rapidSendCancelable() GoCancelable {
 		}
 	})
 }
+
+func BenchmarkSend(b *testing.B) {
+	variants := []struct {
+		name string
+		opts []Option
+	}{
+		{"lock-free", nil},
+		{"locked", []Option{WithSendWatchdog(time.Hour)}}, // A watchdog keeps Send on the locked path
+	}
+	for _, variant := range variants {
+		for _, senders := range []int{1, 8, 64} {
+			b.Run(fmt.Sprintf("%s/senders=%d", variant.name, senders), func(b *testing.B) {
+				cancelable := GoRace(nil, append(variant.opts, WithBuffer(1024))...)
+				cancelable.Drain()
+				defer cancelable.Cancel()
+				var wg sync.WaitGroup
+				b.ResetTimer()
+				for i := 0; i < senders; i++ {
+					wg.Add(1)
+					go func(n int) {
+						defer wg.Done()
+						for j := 0; j < n; j++ {
+							cancelable.Send(j)
+						}
+					}(b.N/senders + 1)
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
+
+func BenchmarkIsCanceled(b *testing.B) {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < b.N; i++ {
+			if cancelable.IsCanceled() {
+				return
+			}
+		}
+	})
+	b.ResetTimer()
+	cancelable.Run(context.Background())
+}
@@ -0,0 +1,62 @@
+// Package goracetest provides helpers for testing code built on gorace cancelables. The helpers fail
+// the test after a timeout instead of hanging when a handler misbehaves
+package goracetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/magic53/gorace"
+)
+
+// DefaultTimeout is how long MustReceive and AssertNoLeak wait before failing the test
+var DefaultTimeout = 5 * time.Second
+
+// MustReceive returns the next result of the cancelable. The test fails immediately if the channel is
+// closed or no result arrives within DefaultTimeout
+func MustReceive(t testing.TB, c gorace.GoCancelable) interface{} {
+	t.Helper()
+	select {
+	case result, ok := <-c.Receive():
+		if !ok {
+			t.Fatal("goracetest: cancelable was canceled before a result was received")
+		}
+		return result
+	case <-time.After(DefaultTimeout):
+		t.Fatalf("goracetest: no result received within %s", DefaultTimeout)
+	}
+	return nil
+}
+
+// AssertCanceledWithin checks that the cancelable is canceled within d. Returns whether the assertion
+// passed
+func AssertCanceledWithin(t testing.TB, c gorace.GoCancelable, d time.Duration) bool {
+	t.Helper()
+	deadline := time.Now().Add(d)
+	for !c.IsCanceled() {
+		if time.Now().After(deadline) {
+			t.Errorf("goracetest: cancelable not canceled within %s", d)
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+// AssertNoLeak drains the cancelable and checks that its channel is closed within DefaultTimeout,
+// meaning the handler returned or the cancelable was canceled. Returns whether the assertion passed
+func AssertNoLeak(t testing.TB, c gorace.GoCancelable) bool {
+	t.Helper()
+	timeout := time.After(DefaultTimeout)
+	for {
+		select {
+		case _, ok := <-c.Receive():
+			if !ok {
+				return true
+			}
+		case <-timeout:
+			t.Errorf("goracetest: cancelable still running after %s, its handler may be leaked", DefaultTimeout)
+			return false
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package goracetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/magic53/gorace"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func TestMustReceive(t *testing.T) {
+	cancelable := gorace.GoRace(func(ctx context.Context, cancelable gorace.GoCancelable) {
+		cancelable.Send(true)
+	}).Start(context.Background())
+
+	assert.Equal(t, true, MustReceive(t, cancelable))
+}
+
+func TestAssertCanceledWithin(t *testing.T) {
+	cancelable := gorace.GoRace(func(ctx context.Context, cancelable gorace.GoCancelable) {
+		<-time.After(10 * time.Millisecond)
+	}).Start(context.Background())
+
+	assert.True(t, AssertCanceledWithin(t, cancelable, time.Second))
+
+	r := &recorder{TB: t}
+	stuck := gorace.GoRace(func(ctx context.Context, cancelable gorace.GoCancelable) {
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stuck.Start(ctx)
+	assert.False(t, AssertCanceledWithin(r, stuck, 10*time.Millisecond))
+	assert.True(t, r.failed, "the assertion should fail")
+}
+
+func TestAssertNoLeak(t *testing.T) {
+	cancelable := gorace.GoRace(func(ctx context.Context, cancelable gorace.GoCancelable) {
+		for i := 0; i < 50; i++ {
+			cancelable.Send(i)
+		}
+	}).Start(context.Background())
+
+	assert.True(t, AssertNoLeak(t, cancelable))
+}
@@ -0,0 +1,47 @@
+package gorace
+
+import "time"
+
+// Group is a batch of results sharing the same key, delivered by cancelables
+// configured with WithResultGrouping
+type Group struct {
+	Key    interface{}
+	Values []interface{}
+}
+
+// Adds a result to its group and schedules a flush if this is the first result of the window. Requires
+// locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) group(result interface{}) {
+	key := gc.groupBy(result)
+	if gc.groups == nil {
+		gc.groups = make(map[interface{}][]interface{})
+	}
+	if _, ok := gc.groups[key]; !ok {
+		gc.groupKeys = append(gc.groupKeys, key)
+	}
+	gc.groups[key] = append(gc.groups[key], result)
+	if gc.groupTimer == nil {
+		gc.groupTimer = time.AfterFunc(gc.flush, gc.flushGroups)
+	}
+}
+
+// Sends each pending group in the order its key was first seen
+func (gc *goCancelable) flushGroups() {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.groupTimer != nil {
+		gc.groupTimer.Stop()
+		gc.groupTimer = nil
+	}
+	// Sends release the lock while waiting, results sent meanwhile start the next window
+	keys, groups := gc.groupKeys, gc.groups
+	gc.groupKeys, gc.groups = nil, nil
+	for _, key := range keys {
+		group := Group{Key: key, Values: groups[key]}
+		if !gc.push(group) {
+			gc.dropped.Add(uint64(len(group.Values)))
+			continue
+		}
+		gc.deliveredAll(group, len(group.Values))
+	}
+}
@@ -0,0 +1,126 @@
+package gorace
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+)
+
+// Logs warnings of cancelables without a logger, replaceable in tests
+var warnf = log.Printf
+
+// GoRaceWithLeakCheck creates a cancelable like GoRace that logs a warning if it is garbage collected
+// after being started but before being canceled, which usually means the handler goroutine is stuck
+// on a Send nobody receives. This is meant for debugging and tests, regular cancelables have no
+// finalizer overhead
+func GoRaceWithLeakCheck(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	return GoRace(handler, append(append([]Option(nil), opts...), WithLeakCheck())...)
+}
+
+// Returns the handle given to callers, wrapped for the leak check if it is enabled
+func (gc *goCancelable) handle() GoCancelable {
+	if !gc.leakCheck {
+		return gc
+	}
+	lc := &leakChecked{goCancelable: gc}
+	runtime.SetFinalizer(lc, checkLeak)
+	return lc
+}
+
+// Wraps a cancelable so the finalizer is tied to the caller's handle. The handler goroutine only
+// references the inner cancelable, otherwise a stuck handler would keep the handle reachable
+type leakChecked struct {
+	*goCancelable
+	started atomic.Bool
+}
+
+// Start marks the handle as started and starts the inner cancelable
+func (lc *leakChecked) Start(ctx context.Context) GoCancelable {
+	lc.started.Store(true)
+	lc.goCancelable.Start(ctx)
+	return lc
+}
+
+// StartE marks the handle as started and starts the inner cancelable
+func (lc *leakChecked) StartE(ctx context.Context) (GoCancelable, error) {
+	lc.started.Store(true)
+	_, err := lc.goCancelable.StartE(ctx)
+	return lc, err
+}
+
+// MustStart marks the handle as started and starts the inner cancelable
+func (lc *leakChecked) MustStart(ctx context.Context) GoCancelable {
+	lc.started.Store(true)
+	lc.goCancelable.MustStart(ctx)
+	return lc
+}
+
+// StartDetached marks the handle as started and starts the inner cancelable
+func (lc *leakChecked) StartDetached(ctx context.Context) GoCancelable {
+	lc.started.Store(true)
+	lc.goCancelable.StartDetached(ctx)
+	return lc
+}
+
+// StartOnce marks the handle as started and starts the inner cancelable
+func (lc *leakChecked) StartOnce(ctx context.Context) bool {
+	lc.started.Store(true)
+	return lc.goCancelable.StartOnce(ctx)
+}
+
+// StartBackground calls Start on a goroutine with the specified context
+func (lc *leakChecked) StartBackground(ctx context.Context) GoCancelable {
+	lc.started.Store(true)
+	lc.goCancelable.StartBackground(ctx)
+	return lc
+}
+
+// Subscribe marks the handle as started and subscribes to the inner cancelable
+func (lc *leakChecked) Subscribe(ctx context.Context, fn func(result interface{})) GoCancelable {
+	lc.started.Store(true)
+	lc.goCancelable.Subscribe(ctx, fn)
+	return lc
+}
+
+// Named sets the name of the inner cancelable
+func (lc *leakChecked) Named(name string) GoCancelable {
+	lc.goCancelable.Named(name)
+	return lc
+}
+
+// Buffered sets the buffer of the inner cancelable
+func (lc *leakChecked) Buffered(n int) GoCancelable {
+	lc.goCancelable.Buffered(n)
+	return lc
+}
+
+// Logged sets the logger of the inner cancelable
+func (lc *leakChecked) Logged(l *slog.Logger) GoCancelable {
+	lc.goCancelable.Logged(l)
+	return lc
+}
+
+// Finalizer for leak checked handles. Reads the quit channel instead of locking since a leaked
+// handler may be holding the lock in a blocked Send
+func checkLeak(lc *leakChecked) {
+	if !lc.started.Load() {
+		return
+	}
+	select {
+	case <-lc.quit:
+	default:
+		// The logger and name are only set while idle, reading them can't race
+		msg := "gorace: cancelable was garbage collected while started and not canceled, its handler goroutine may be leaked"
+		if lc.name != "" {
+			msg = fmt.Sprintf("gorace: cancelable %q was garbage collected while started and not canceled, its handler goroutine may be leaked", lc.name)
+		}
+		if lc.logger != nil {
+			lc.logger.Warn(msg)
+		} else {
+			warnf("%s", msg)
+		}
+	}
+}
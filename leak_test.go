@@ -0,0 +1,64 @@
+package gorace
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceLeakCheck() {
+	warnings := make(chan string, 2)
+	warnf = func(format string, v ...interface{}) {
+		warnings <- fmt.Sprintf(format, v...)
+	}
+	defer func() { warnf = log.Printf }()
+
+	inner := make(chan GoCancelable, 1)
+	func() {
+		GoRaceWithLeakCheck(func(ctx context.Context, cancelable GoCancelable) {
+			inner <- cancelable
+			cancelable.Send(1)
+			cancelable.Send(2) // blocks until drained
+		}).Named("leaky").Start(context.Background())
+		finished := GoRaceWithLeakCheck(func(ctx context.Context, cancelable GoCancelable) {}).Start(context.Background())
+		for range finished.Receive() {
+		}
+	}()
+	leaked := <-inner
+
+	select {
+	case warning := <-warnings:
+		suite.Contains(warning, "may be leaked")
+		suite.Contains(warning, `"leaky"`, "the warning should name the cancelable")
+	case <-collect(2 * time.Second):
+		suite.Fail("leak warning should be logged")
+	}
+
+	for range leaked.Receive() {
+	}
+	select {
+	case <-warnings:
+		suite.Fail("only the leaked cancelable should be reported")
+	default:
+	}
+}
+
+// Runs the garbage collector until d elapses
+func collect(d time.Duration) <-chan time.Time {
+	timeout := time.After(d)
+	done := make(chan time.Time, 1)
+	go func() {
+		for {
+			runtime.GC()
+			select {
+			case t := <-timeout:
+				done <- t
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+	return done
+}
@@ -0,0 +1,37 @@
+package gorace
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logs a lifecycle event along with the name, state and elapsed time of the cancelable. Does nothing
+// without a logger. Reads the status with the lock, so it must never be called while holding it. The
+// logger is only set while idle, reading it can't race
+func (gc *goCancelable) logEvent(level slog.Level, msg string, attrs ...slog.Attr) {
+	if gc.logger == nil || !gc.logger.Enabled(context.Background(), level) {
+		return
+	}
+	status := gc.Snapshot()
+	attrs = append(attrs,
+		slog.String("name", status.Name),
+		slog.String("state", status.State),
+		slog.Duration("elapsed", status.Elapsed),
+	)
+	gc.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// Logs the end of the cancelable, at a level depending on how it ended
+func (gc *goCancelable) logStopped(cause error) {
+	if gc.logger == nil {
+		return
+	}
+	switch {
+	case cause != nil:
+		gc.logEvent(slog.LevelWarn, "gorace: canceled", slog.Any("cause", cause))
+	case gc.Reason() == ReasonCompleted:
+		gc.logEvent(slog.LevelDebug, "gorace: completed")
+	default:
+		gc.logEvent(slog.LevelInfo, "gorace: canceled")
+	}
+}
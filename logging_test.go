@@ -0,0 +1,35 @@
+package gorace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceWithLogger() {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2) // dropped, the channel is full
+	}, WithName("fetch"), WithLossySend(), WithLogger(logger))
+	cancelable.Start(context.Background())
+	<-cancelable.Done()
+
+	suite.Contains(logs.String(), `level=DEBUG msg="gorace: started" name=fetch state=running`)
+	suite.Contains(logs.String(), `level=DEBUG msg="gorace: result dropped" name=fetch`)
+	suite.Contains(logs.String(), `level=DEBUG msg="gorace: completed" name=fetch state=completed elapsed=`)
+
+	logs.Reset()
+	failure := errors.New("request failed")
+	panicking := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		panic(failure)
+	}, WithLogger(logger))
+	panicking.Start(context.Background())
+	<-panicking.Done()
+
+	suite.Contains(logs.String(), `level=ERROR msg="gorace: handler panicked" panic="request failed"`)
+	suite.Contains(logs.String(), `level=WARN msg="gorace: canceled" cause=`)
+}
@@ -0,0 +1,35 @@
+package gorace
+
+// Sends the result if the channel has room, otherwise drops it and counts the drop. Never blocks and never
+// panics, even if a caller-supplied channel was closed behind the cancelable's back. Grouping, batching
+// and the error channel are bypassed. Returns false if the result was dropped
+func (gc *goCancelable) sendLossy(result interface{}) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			gc.dropped.Add(1)
+			sent = false
+		}
+	}()
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.canceled.Load() {
+		gc.dropped.Add(1)
+		return false
+	}
+	result = gc.prepare(result)
+	select {
+	case gc.send <- result:
+		gc.delivered(result)
+		return true
+	default:
+		gc.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns the number of results dropped because the channel was full for a lossy send, a
+// SendTimeout timed out, the rate limiter had no token for them or the cancelable was canceled before the
+// send
+func (gc *goCancelable) Dropped() uint64 {
+	return gc.dropped.Load()
+}
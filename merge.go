@@ -0,0 +1,190 @@
+package gorace
+
+import (
+	"context"
+	"reflect"
+)
+
+// MergeStrategy decides which input a merged cancelable sends from when
+// several inputs have results available
+type MergeStrategy int
+
+const (
+	// MergeArrival sends results in the order they were received
+	MergeArrival MergeStrategy = iota
+	// MergeRoundRobin takes turns between the inputs
+	MergeRoundRobin
+	// MergePriority prefers inputs passed earlier
+	MergePriority
+	// MergeTimestamp sends the result with the earliest Timestamped time
+	// first, results without a timestamp are sent in arrival order
+	MergeTimestamp
+)
+
+// Merge returns a cancelable sending the results of every input as they arrive. It is canceled once all
+// inputs are, and starting or canceling it does the same to every input
+func Merge(cs ...GoCancelable) GoCancelable {
+	return MergeWith(cs)
+}
+
+// MergeWith is like Merge but applies opts to the merged cancelable, see WithMergeStrategy
+func MergeWith(cs []GoCancelable, opts ...Option) GoCancelable {
+	var stage *goCancelable
+	stage = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+		for _, c := range cs {
+			c.Start(ctx)
+		}
+		m := &merger{stage: stage, inputs: cs, heads: make([]*mergeHead, len(cs)), open: make([]bool, len(cs))}
+		for i := range m.open {
+			m.open[i] = true
+		}
+		m.run()
+	}, opts...)
+	stage.children = append(stage.children, cs...)
+	return stage.handle()
+}
+
+// A result received from an input and waiting to be sent
+type mergeHead struct {
+	result  interface{}
+	arrival uint64
+}
+
+// State of the merged cancelable's handler. Each input holds at most one received result, the head,
+// which the strategy picks from, so a busy input can't starve the others
+type merger struct {
+	stage    *goCancelable
+	inputs   []GoCancelable
+	heads    []*mergeHead
+	open     []bool
+	arrivals uint64
+	next     int
+}
+
+// Sends results until every input is closed or the merged cancelable is canceled
+func (m *merger) run() {
+	for {
+		m.fill()
+		i := m.pick()
+		if i < 0 {
+			if !m.wait() {
+				return
+			}
+			continue
+		}
+		head := m.heads[i]
+		m.heads[i] = nil
+		m.stage.Send(head.result)
+		select {
+		case <-m.stage.quit:
+			m.drain()
+			return
+		default:
+		}
+	}
+}
+
+// Receives a head from every open input that has a result ready
+func (m *merger) fill() {
+	for i, c := range m.inputs {
+		if !m.open[i] || m.heads[i] != nil {
+			continue
+		}
+		select {
+		case result, ok := <-c.Receive():
+			m.received(i, result, ok)
+		default:
+		}
+	}
+}
+
+// Blocks until an open input has a result or closes. Returns false once all inputs are closed or the
+// merged cancelable is canceled
+func (m *merger) wait() bool {
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(m.stage.quit)}}
+	var indexes []int
+	for i, c := range m.inputs {
+		if m.open[i] {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Receive())})
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return false
+	}
+	chosen, result, ok := reflect.Select(cases)
+	if chosen == 0 {
+		m.drain()
+		return false
+	}
+	var v interface{}
+	if ok {
+		v = result.Interface()
+	}
+	m.received(indexes[chosen-1], v, ok)
+	return true
+}
+
+// Stores a received result as the head of input i or marks the input closed
+func (m *merger) received(i int, result interface{}, ok bool) {
+	if !ok {
+		m.open[i] = false
+		return
+	}
+	m.arrivals++
+	m.heads[i] = &mergeHead{result: result, arrival: m.arrivals}
+}
+
+// Returns the index of the head to send next according to the strategy, or -1 if there's none
+func (m *merger) pick() int {
+	picked := -1
+	switch m.stage.merge {
+	case MergePriority:
+		for i, head := range m.heads {
+			if head != nil {
+				return i
+			}
+		}
+	case MergeRoundRobin:
+		for n := 0; n < len(m.heads); n++ {
+			if i := (m.next + n) % len(m.heads); m.heads[i] != nil {
+				m.next = i + 1
+				return i
+			}
+		}
+	case MergeTimestamp:
+		for i, head := range m.heads {
+			if head != nil && (picked < 0 || earlier(head, m.heads[picked])) {
+				picked = i
+			}
+		}
+	default:
+		for i, head := range m.heads {
+			if head != nil && (picked < 0 || head.arrival < m.heads[picked].arrival) {
+				picked = i
+			}
+		}
+	}
+	return picked
+}
+
+// Returns true if a should be sent before b by timestamp, falling back to arrival order when either
+// result isn't timestamped
+func earlier(a, b *mergeHead) bool {
+	av, _ := untrace(a.result)
+	bv, _ := untrace(b.result)
+	at, aok := av.(Timestamped)
+	bt, bok := bv.(Timestamped)
+	if aok && bok && !at.At.Equal(bt.At) {
+		return at.At.Before(bt.At)
+	}
+	return a.arrival < b.arrival
+}
+
+// Discards the remaining results of every input so their handlers can't stay blocked on Send while the
+// merged cancelable cancels them
+func (m *merger) drain() {
+	for _, c := range m.inputs {
+		c.Drain()
+	}
+}
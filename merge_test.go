@@ -0,0 +1,121 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+// Returns a finished cancelable holding the results in its buffer
+func bufferedCancelable(results ...interface{}) GoCancelable {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for _, result := range results {
+			cancelable.Send(result)
+		}
+	}).Buffered(len(results))
+	cancelable.Start(context.Background())
+	<-cancelable.(*goCancelable).done
+	return cancelable
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMergeRoundRobin() {
+	merged := MergeWith([]GoCancelable{
+		bufferedCancelable("a0", "a1", "a2"),
+		bufferedCancelable("b0", "b1", "b2"),
+	}, WithMergeStrategy(MergeRoundRobin))
+	merged.Start(context.Background())
+
+	var results []interface{}
+	for result := range merged.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{"a0", "b0", "a1", "b1", "a2", "b2"}, results, "inputs should take turns")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMergePriority() {
+	merged := MergeWith([]GoCancelable{
+		bufferedCancelable("a0", "a1", "a2"),
+		bufferedCancelable("b0", "b1", "b2"),
+	}, WithMergeStrategy(MergePriority))
+	merged.Start(context.Background())
+
+	var results []interface{}
+	for result := range merged.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{"a0", "a1", "a2", "b0", "b1", "b2"}, results, "earlier inputs should be preferred")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMergeTimestamp() {
+	at := time.Now()
+	merged := MergeWith([]GoCancelable{
+		bufferedCancelable(Timestamped{At: at.Add(1), Value: 1}, Timestamped{At: at.Add(4), Value: 4}),
+		bufferedCancelable(Timestamped{At: at.Add(2), Value: 2}, Timestamped{At: at.Add(3), Value: 3}),
+	}, WithMergeStrategy(MergeTimestamp))
+	merged.Start(context.Background())
+
+	var results []interface{}
+	for result := range merged.Receive() {
+		results = append(results, result.(Timestamped).Value)
+	}
+
+	suite.Equal([]interface{}{1, 2, 3, 4}, results, "results should be sent in timestamp order")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMerge() {
+	traced := Traced{Ctx: context.Background(), Value: "traced"}
+	merged := Merge(
+		bufferedCancelable(1, 2, 3),
+		GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			cancelable.Send(traced)
+		}),
+		bufferedCancelable(4),
+	)
+	merged.Start(context.Background())
+
+	var results []interface{}
+	for result := range merged.Receive() {
+		results = append(results, result)
+	}
+
+	suite.ElementsMatch([]interface{}{1, 2, 3, 4, traced}, results, "every result of every input should be sent")
+	suite.Equal(ReasonCompleted, merged.Reason(), "merged should complete once every input closed")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMergeCancel() {
+	inputs := []GoCancelable{rapidSendCancelable(), rapidSendCancelable()}
+	merged := Merge(inputs...)
+	merged.Start(context.Background())
+
+	merged.Drain()
+	merged.Cancel()
+
+	for _, input := range inputs {
+		suite.Eventually(input.IsCanceled, time.Second, 10*time.Millisecond, "canceling merged should cancel every input")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMergeNoStarvation() {
+	fast := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; ctx.Err() == nil; i++ {
+			cancelable.Send(i)
+		}
+	})
+	slow := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-time.After(20 * time.Millisecond)
+		cancelable.Send("slow")
+	})
+	merged := Merge(fast, slow)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	merged.Start(ctx)
+	defer merged.Shutdown(ctx)
+
+	for result := range merged.Receive() {
+		if result == "slow" {
+			return
+		}
+	}
+	suite.Fail("a slow input should not be starved by a fast one")
+}
@@ -0,0 +1,63 @@
+package gorace
+
+import "context"
+
+// Envelope is the result delivered by GoRaceMeta cancelables. Meta carries
+// the metadata passed to SendWithMeta and is nil for results sent with Send
+type Envelope struct {
+	Value interface{}
+	Meta  map[string]interface{}
+}
+
+// GoMetaCancelable contract. Equivalent to GoCancelable except results can
+// carry metadata and are delivered as Envelope values
+type GoMetaCancelable interface {
+	// Cancel closes the internal channel and returns true. If the
+	// cancelable is already canceled this returns false
+	Cancel() bool
+	// Send sends a result without metadata to channel listeners
+	Send(result interface{})
+	// SendWithMeta sends a result along with its metadata to channel
+	// listeners
+	SendWithMeta(result interface{}, meta map[string]interface{})
+	// Receive returns the envelope channel
+	Receive() <-chan Envelope
+	// Start runs the userdefined handler func. The specified context is
+	// passed through to the handler func
+	Start(ctx context.Context) GoMetaCancelable
+	// StartBackground starts the cancelable on a goroutine
+	StartBackground(ctx context.Context) GoMetaCancelable
+	// LastResult returns the last envelope sent successfully on the channel
+	LastResult() Envelope
+	// Cause returns the error the cancelable was canceled with, if any
+	Cause() error
+	// IsCanceled returns true if the cancelable is canceled otherwise
+	// returns false
+	IsCanceled() bool
+}
+
+// GoRaceMeta creates a cancelable whose handler can attach metadata such as sequence numbers to each result,
+// which consumers read from Envelope.Meta while the result type stays free of it
+func GoRaceMeta(handler func(ctx context.Context, cancelable GoMetaCancelable), opts ...Option) GoMetaCancelable {
+	mc := &goMetaCancelable{}
+	mc.wrapper = newWrapper[Envelope, GoMetaCancelable](mc, func(ctx context.Context, _ GoCancelable) {
+		handler(ctx, mc)
+	}, opts...)
+	return mc
+}
+
+// Implementation of GoMetaCancelable on top of a regular cancelable
+type goMetaCancelable struct {
+	*wrapper[Envelope, GoMetaCancelable]
+}
+
+// Send sends the result wrapped in an Envelope without metadata
+func (mc *goMetaCancelable) Send(result interface{}) {
+	mc.gc.Send(Envelope{Value: result})
+}
+
+// SendWithMeta sends the result wrapped in an Envelope with the metadata. The map isn't copied, it must not
+// be modified once sent
+func (mc *goMetaCancelable) SendWithMeta(result interface{}, meta map[string]interface{}) {
+	mc.gc.Send(Envelope{Value: result, Meta: meta})
+}
@@ -0,0 +1,44 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceMeta() {
+	cancelable := GoRaceMeta(func(ctx context.Context, cancelable GoMetaCancelable) {
+		for seq := 0; seq < 3; seq++ {
+			cancelable.SendWithMeta("chunk", map[string]interface{}{"seq": seq})
+		}
+		cancelable.Send("eof")
+	})
+	cancelable.Start(context.Background())
+
+	var results []Envelope
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Require().Len(results, 4)
+	for seq, result := range results[:3] {
+		suite.Equal("chunk", result.Value)
+		suite.Equal(seq, result.Meta["seq"], "the metadata should be delivered with its result")
+	}
+	suite.Equal(Envelope{Value: "eof"}, results[3], "results sent with Send should have no metadata")
+	suite.Equal(Envelope{Value: "eof"}, cancelable.LastResult(), "cancelable.LastResult() should be the last Envelope")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMetaUnexpectedType() {
+	cancelable := GoRaceMeta(func(ctx context.Context, cancelable GoMetaCancelable) {
+		cancelable.Send(1)
+		<-ctx.Done()
+	}, WithResultTimestamp())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cancelable.Start(ctx)
+
+	for range cancelable.Receive() {
+		suite.Fail("a Timestamped value should not be received as an Envelope")
+	}
+	suite.ErrorIs(cancelable.Cause(), ErrUnexpectedType)
+}
@@ -0,0 +1,15 @@
+package gorace
+
+// Observer is notified of the lifecycle of a cancelable configured with
+// WithObserver. Callbacks are called without holding the cancelable's lock,
+// so they may call its methods
+type Observer interface {
+	// Started is called on the handler goroutine before the handler
+	Started(c GoCancelable)
+	// Sent is called after Send handed a result to a cancelable that wasn't
+	// canceled
+	Sent(c GoCancelable, result interface{})
+	// Canceled is called once the cancelable is canceled, with the cause
+	// of the cancel if any
+	Canceled(c GoCancelable, cause error)
+}
@@ -0,0 +1,313 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Map returns a cancelable whose results are fn applied to each result of this cancelable
+func (gc *goCancelable) Map(fn func(result interface{}) interface{}) GoCancelable {
+	return forward(gc, func(result interface{}, send func(result interface{})) {
+		send(fn(result))
+	})
+}
+
+// Filter returns a cancelable forwarding only the results of this cancelable for which pred returns true
+func (gc *goCancelable) Filter(pred func(result interface{}) bool) GoCancelable {
+	return forward(gc, func(result interface{}, send func(result interface{})) {
+		if pred(result) {
+			send(result)
+		}
+	})
+}
+
+// Until returns a cancelable forwarding the results of this cancelable until pred returns true for one. That
+// result is still forwarded, then the returned cancelable completes and this cancelable is canceled. The
+// remaining results are discarded so the handler can't stay blocked on Send
+func (gc *goCancelable) Until(pred func(result interface{}) bool) GoCancelable {
+	var stage GoCancelable
+	stage = forward(gc, func(result interface{}, send func(result interface{})) {
+		send(result)
+		if pred(result) {
+			gc.Drain()
+			stage.CloseSend()
+		}
+	})
+	return stage
+}
+
+// Then returns a cancelable calling next for each result of this cancelable, one at a time. next sends the
+// results of the returned cancelable on the cancelable it is passed. Starting or canceling the returned
+// cancelable does the same to this cancelable
+func (gc *goCancelable) Then(next func(ctx context.Context, result interface{}, cancelable GoCancelable)) GoCancelable {
+	return gc.ThenTimeout(0, next)
+}
+
+// ThenTimeout is like Then but gives each call of next at most d, 0 meaning no limit. A call still running
+// after d cancels the returned cancelable, and with it the stages before it, with a *StageTimeoutError
+// without waiting for next to return, so a hanging stage can't stall the chain. The stages after it are
+// canceled with the same cause once their source closes
+func (gc *goCancelable) ThenTimeout(d time.Duration, next func(ctx context.Context, result interface{}, cancelable GoCancelable)) GoCancelable {
+	var stage *goCancelable
+	stage = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+		results := gc.Start(ctx).Receive()
+		for {
+			select {
+			case result, ok := <-results:
+				if !ok {
+					var timeout *StageTimeoutError
+					if errors.As(gc.Cause(), &timeout) {
+						stage.CancelCause(timeout)
+					}
+					return
+				}
+				if !stage.runStage(ctx, d, result, next) {
+					return
+				}
+			case <-stage.quit:
+				return
+			}
+		}
+	}, WithSourceID(gc.SourceID()))
+	stage.depth = gc.depth + 1
+	// Canceled like a child, but with the stage's cause so a timeout reaches the stages before it
+	stage.hooks = append(stage.hooks, func() {
+		gc.CancelCause(stage.Cause())
+	})
+	return stage
+}
+
+// Calls next for a result of the previous stage, on another goroutine if it has a timeout so the stage can
+// give up on it. Returns false if the stage timed out or was canceled while waiting
+func (gc *goCancelable) runStage(ctx context.Context, d time.Duration, result interface{}, next func(ctx context.Context, result interface{}, cancelable GoCancelable)) bool {
+	if d <= 0 {
+		next(ctx, result, gc)
+		return true
+	}
+	timeout := &StageTimeoutError{Stage: gc.depth, Timeout: d}
+	ctx, cancel := context.WithTimeoutCause(ctx, d, timeout)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer gc.recoverPanic()
+		next(ctx, result, gc)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		if context.Cause(ctx) == timeout {
+			gc.CancelCause(timeout)
+		}
+		return false
+	}
+}
+
+// ConcurrentMap returns a cancelable whose results are fn applied to each result of src by up to
+// concurrency workers. Results are sent in the order src sent them regardless of which worker finishes
+// first. Starting or canceling the returned cancelable does the same to src
+func ConcurrentMap(src GoCancelable, concurrency int, fn func(result interface{}) interface{}) GoCancelable {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	type sequenced struct {
+		seq    int
+		result interface{}
+	}
+	var stage *goCancelable
+	stage = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+		jobs := make(chan sequenced)
+		mapped := make(chan sequenced)
+		go func() {
+			defer close(jobs)
+			results := src.Start(ctx).Receive()
+			for seq := 0; ; seq++ {
+				select {
+				case result, ok := <-results:
+					if !ok {
+						return
+					}
+					select {
+					case jobs <- sequenced{seq, result}:
+					case <-stage.quit:
+						return
+					}
+				case <-stage.quit:
+					return
+				}
+			}
+		}()
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					result, retrace := untrace(job.result)
+					mapped <- sequenced{job.seq, retrace(fn(result))}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(mapped)
+		}()
+		// Reorder buffer holding results that finished ahead of their turn
+		pending := make(map[int]interface{})
+		next := 0
+		for job := range mapped {
+			pending[job.seq] = job.result
+			for result, ok := pending[next]; ok; result, ok = pending[next] {
+				delete(pending, next)
+				stage.Send(result)
+				next++
+			}
+		}
+	}, WithSourceID(src.SourceID()))
+	stage.children = append(stage.children, src)
+	return stage
+}
+
+// Pluck returns a cancelable yielding the named field of each struct result of src. Pointers to structs
+// are followed. Results without an exported field of that name are replaced by an error wrapping
+// ErrFieldNotFound. Starting or canceling the returned cancelable does the same to src
+func Pluck(src GoCancelable, field string) GoCancelable {
+	return forward(src, func(result interface{}, send func(result interface{})) {
+		v := reflect.Indirect(reflect.ValueOf(result))
+		if v.Kind() == reflect.Struct {
+			if f := v.FieldByName(field); f.IsValid() && f.CanInterface() {
+				send(f.Interface())
+				return
+			}
+		}
+		send(fmt.Errorf("%w: %q in %T", ErrFieldNotFound, field, result))
+	})
+}
+
+// Sample returns a cancelable sending a uniformly random sample of n results of src once src closes.
+// Starting or canceling the returned cancelable does the same to src
+func Sample(src GoCancelable, n int) GoCancelable {
+	return SampleRand(src, n, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// SampleRand is like Sample but draws the sample using rng
+func SampleRand(src GoCancelable, n int, rng *rand.Rand) GoCancelable {
+	if n < 0 {
+		n = 0
+	}
+	var stage *goCancelable
+	stage = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+		results := src.Start(ctx).Receive()
+		// Reservoir sampling: the i-th result replaces a random slot with probability n/(i+1)
+		reservoir := make([]interface{}, 0, n)
+		for seen := 0; ; seen++ {
+			select {
+			case result, ok := <-results:
+				if !ok {
+					for _, result := range reservoir {
+						stage.Send(result)
+					}
+					return
+				}
+				if seen < n {
+					reservoir = append(reservoir, result)
+				} else if slot := rng.Intn(seen + 1); slot < n {
+					reservoir[slot] = result
+				}
+			case <-stage.quit:
+				return
+			}
+		}
+	}, WithSourceID(src.SourceID()))
+	stage.children = append(stage.children, src)
+	return stage
+}
+
+// Tee returns two cancelables each receiving every result of src. Starting either starts src, without tying
+// src to the context of that branch. Each branch queues the results its consumer didn't receive yet, so a
+// slow consumer doesn't hold up the other one, at the cost of memory growing with the backlog. Canceling a
+// branch leaves the other one running, canceling both cancels src and canceling src completes both
+func Tee(src GoCancelable) (GoCancelable, GoCancelable) {
+	var branches [2]*goCancelable
+	var started sync.Once
+	var canceled atomic.Int32
+	pumped := make(chan struct{})
+	pump := func(ctx context.Context) {
+		defer close(pumped)
+		for result := range src.Start(context.WithoutCancel(ctx)).Receive() {
+			for _, branch := range branches {
+				branch.Send(result) // never blocks, results are dropped once the branch is canceled
+			}
+		}
+	}
+	for i := range branches {
+		branches[i] = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+			started.Do(func() {
+				go pump(ctx)
+			})
+			select {
+			case <-pumped:
+			case <-ctx.Done():
+			}
+		}, WithSpillover(0, SpillBlock), WithSourceID(src.SourceID()))
+		branches[i].OnCancel(func() {
+			if canceled.Add(1) == int32(len(branches)) {
+				src.Cancel()
+			}
+		})
+	}
+	return branches[0].handle(), branches[1].handle()
+}
+
+// Creates a cancelable that passes each result of src to fn, along with the function sending the stage's
+// results. Traced results are unwrapped before calling fn and their context is attached to whatever fn
+// sends for them. Starting the stage starts src with the same context and canceling the stage cancels
+// src. The stage is canceled once src is canceled, and stops forwarding as soon as it is canceled itself
+func forward(src GoCancelable, fn func(result interface{}, send func(result interface{}))) GoCancelable {
+	var stage *goCancelable
+	stage = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+		results := src.Start(ctx).Receive()
+		for {
+			select {
+			case result, ok := <-results:
+				if !ok {
+					return
+				}
+				result, retrace := untrace(result)
+				fn(result, func(result interface{}) {
+					stage.Send(retrace(result))
+				})
+			case <-stage.quit:
+				return
+			}
+		}
+	}, WithSourceID(src.SourceID()))
+	stage.children = append(stage.children, src)
+	return stage
+}
+
+// Traced attaches a context, typically carrying trace or span data, to a result. Operators like Map and
+// Filter work on Value and keep Ctx attached to the results they derive from it
+type Traced struct {
+	Ctx   context.Context
+	Value interface{}
+}
+
+// Unwraps a traced result, returning its value and a function attaching the same context to a derived
+// result. Other results are returned as they are
+func untrace(result interface{}) (interface{}, func(derived interface{}) interface{}) {
+	traced, ok := result.(Traced)
+	if !ok {
+		return result, func(derived interface{}) interface{} { return derived }
+	}
+	return traced.Value, func(derived interface{}) interface{} {
+		return Traced{Ctx: traced.Ctx, Value: derived}
+	}
+}
@@ -0,0 +1,307 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceMap() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 5; i++ {
+			cancelable.Send(i)
+		}
+	})
+	mapped := cancelable.Map(func(result interface{}) interface{} {
+		return result.(int) * 2
+	})
+	mapped.Start(context.Background())
+
+	var results []interface{}
+	for result := range mapped.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 2, 4, 6, 8}, results)
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+	suite.Equal(true, mapped.IsCanceled(), "mapped.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceMapPropagatesCancel() {
+	cancelable := rapidSendCancelable()
+	mapped := cancelable.Map(func(result interface{}) interface{} {
+		return !result.(bool)
+	})
+
+	mapped.Cancel()
+
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceFilter() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 6; i++ {
+			cancelable.Send(i)
+		}
+	})
+	filtered := cancelable.Filter(func(result interface{}) bool {
+		return result.(int)%2 == 0
+	})
+	filtered.Start(context.Background())
+
+	var results []interface{}
+	for result := range filtered.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 2, 4}, results)
+	suite.Equal(4, filtered.LastResult(), "filtered.LastResult() should ignore dropped results")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceFilterStopsOnCancel() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	})
+	filtered := cancelable.Filter(func(result interface{}) bool { return true })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	filtered.Start(ctx)
+
+	filtered.Cancel()
+
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+	_, ok := <-filtered.Receive()
+	suite.False(ok, "filtered.Receive() should be closed")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceUntil() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; ctx.Err() == nil; i++ {
+			cancelable.Send(i)
+		}
+	})
+	until := cancelable.Until(func(result interface{}) bool {
+		return result == 3
+	})
+	until.Start(context.Background())
+
+	var results []interface{}
+	for result := range until.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2, 3}, results, "results should stop after the one matching the predicate")
+	select {
+	case <-cancelable.Done():
+	case <-time.After(time.Second):
+		suite.Fail("the source handler should return once the predicate matched")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceThen() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2)
+	})
+	chained := cancelable.Then(func(ctx context.Context, result interface{}, cancelable GoCancelable) {
+		cancelable.Send(result.(int) * 10)
+	})
+	chained.Start(context.Background())
+
+	var results []interface{}
+	for result := range chained.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{10, 20}, results)
+	suite.NoError(chained.Cause())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceThenTimeout() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send("id")
+		<-ctx.Done()
+	})
+	fast := cancelable.ThenTimeout(time.Second, func(ctx context.Context, result interface{}, cancelable GoCancelable) {
+		cancelable.Send(result.(string) + ":fetched")
+	})
+	slow := fast.ThenTimeout(10*time.Millisecond, func(ctx context.Context, result interface{}, cancelable GoCancelable) {
+		time.Sleep(time.Second) // ignores ctx like a stuck call would
+		cancelable.Send(result)
+	})
+	last := slow.Then(func(ctx context.Context, result interface{}, cancelable GoCancelable) {
+		cancelable.Send(result)
+	})
+	start := time.Now()
+	last.Start(context.Background())
+
+	for range last.Receive() {
+		suite.Fail("the slow stage should time out before sending")
+	}
+
+	suite.Less(time.Since(start), time.Second, "the chain shouldn't wait for the slow stage")
+	suite.ErrorIs(last.Cause(), ErrStageTimeout)
+	var timeout *StageTimeoutError
+	suite.ErrorAs(last.Cause(), &timeout)
+	suite.Equal(2, timeout.Stage, "the second stage should have timed out")
+	suite.Equal(10*time.Millisecond, timeout.Timeout)
+	suite.ErrorIs(slow.Cause(), ErrStageTimeout, "the stage that timed out should have the cause")
+	suite.Eventually(func() bool {
+		return errors.Is(fast.Cause(), ErrStageTimeout)
+	}, time.Second, time.Millisecond, "the stages before it should be canceled with the cause")
+	suite.Eventually(func() bool {
+		return errors.Is(cancelable.Cause(), ErrStageTimeout)
+	}, time.Second, time.Millisecond, "the source should be canceled with the cause")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceTee() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 10; i++ {
+			cancelable.Send(i)
+		}
+	})
+	fast, slow := Tee(cancelable)
+	fast.Start(context.Background())
+	slow.Start(context.Background())
+
+	var fastResults, slowResults []interface{}
+	for result := range fast.Receive() {
+		fastResults = append(fastResults, result)
+	}
+	for result := range slow.Receive() {
+		slowResults = append(slowResults, result)
+	}
+
+	expected := []interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	suite.Equal(expected, fastResults, "a branch shouldn't wait for the other one to be consumed")
+	suite.Equal(expected, slowResults, "the slow branch should still get every result")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceTeeCancel() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; ctx.Err() == nil; i++ {
+			cancelable.Send(i)
+		}
+	})
+	left, right := Tee(cancelable)
+	left.Start(context.Background())
+	right.Start(context.Background())
+
+	left.Cancel()
+	suite.Equal(0, <-right.Receive(), "canceling one branch should leave the other one running")
+	suite.False(cancelable.IsCanceled(), "the source should run while a branch is left")
+
+	right.Cancel()
+	select {
+	case <-cancelable.Done():
+	case <-time.After(time.Second):
+		suite.Fail("canceling both branches should cancel the source")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceConcurrentMap() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 10; i++ {
+			cancelable.Send(i)
+		}
+	})
+	mapped := ConcurrentMap(cancelable, 4, func(result interface{}) interface{} {
+		// Earlier results take longer so workers finish out of order
+		time.Sleep(time.Duration(10-result.(int)) * 5 * time.Millisecond)
+		return result.(int) * 10
+	})
+	mapped.Start(context.Background())
+
+	var results []interface{}
+	for result := range mapped.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}, results, "results should keep the source order")
+}
+
+func (suite *GoRaceTestSuite) TestGoRacePluck() {
+	type user struct {
+		Name string
+		Age  int
+	}
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(user{Name: "ada", Age: 36})
+		cancelable.Send(&user{Name: "grace", Age: 85})
+		cancelable.Send("not a struct")
+	})
+	plucked := Pluck(cancelable, "Name")
+	plucked.Start(context.Background())
+
+	suite.Equal("ada", <-plucked.Receive())
+	suite.Equal("grace", <-plucked.Receive(), "pointers to structs should be followed")
+	err, ok := (<-plucked.Receive()).(error)
+	suite.Require().True(ok, "results without the field should be replaced by an error")
+	suite.ErrorIs(err, ErrFieldNotFound)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceTracePropagation() {
+	type traceKey struct{}
+	trace := context.WithValue(context.Background(), traceKey{}, "span-1")
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(Traced{Ctx: trace, Value: 1})
+		cancelable.Send(Traced{Ctx: trace, Value: 2})
+	})
+	stage := cancelable.Map(func(result interface{}) interface{} {
+		return result.(int) * 10
+	}).Filter(func(result interface{}) bool {
+		return result.(int) > 10
+	})
+	stage.Start(context.Background())
+
+	var results []interface{}
+	for result := range stage.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Require().Len(results, 1)
+	traced, ok := results[0].(Traced)
+	suite.Require().True(ok, "results should stay Traced across stages")
+	suite.Equal(20, traced.Value, "stages should operate on the traced value")
+	suite.Equal("span-1", traced.Ctx.Value(traceKey{}), "the trace context should be intact downstream")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSample() {
+	source := func() GoCancelable {
+		return GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			for i := 0; i < 100; i++ {
+				cancelable.Send(i)
+			}
+		})
+	}
+	sample := func(rng *rand.Rand) []interface{} {
+		sampled := SampleRand(source(), 5, rng).Start(context.Background())
+		var results []interface{}
+		for result := range sampled.Receive() {
+			results = append(results, result)
+		}
+		return results
+	}
+
+	results := sample(rand.New(rand.NewSource(1)))
+
+	suite.Equal([]interface{}{46, 43, 78, 62, 13}, results)
+	suite.Equal(results, sample(rand.New(rand.NewSource(1))), "the same seed should draw the same sample")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSampleShortStream() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	})
+	sampled := Sample(cancelable, 5).Start(context.Background())
+
+	var results []interface{}
+	for result := range sampled.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2}, results, "a short stream should be sampled whole")
+}
@@ -0,0 +1,274 @@
+package gorace
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Option configures a cancelable at construction. Options are applied once,
+// in order, when the cancelable is created and can't be changed afterwards
+type Option func(gc *goCancelable)
+
+// Timestamped wraps a result with the time it was sent
+type Timestamped struct {
+	At    time.Time
+	Value interface{}
+}
+
+// WithResultTimestamp wraps each sent result in a Timestamped recording the
+// send time. Receivers get Timestamped values instead of the raw results
+func WithResultTimestamp() Option {
+	return func(gc *goCancelable) {
+		gc.timestamp = true
+	}
+}
+
+// WithCascadeUp makes a derived cancelable cancel its parent when it is
+// canceled with an error. Cancels without a cause only affect the child
+func WithCascadeUp() Option {
+	return func(gc *goCancelable) {
+		gc.cascadeUp = true
+	}
+}
+
+// WithResultSink consumes each sent result by calling sink from a goroutine
+// started alongside the handler, instead of exposing them on Receive(). A
+// sink error cancels the cancelable with the error as the cause
+func WithResultSink(sink func(v interface{}) error) Option {
+	return func(gc *goCancelable) {
+		gc.sink = sink
+	}
+}
+
+// Replayable records every delivered result so the run can be replayed with
+// Replay. The recorded history grows with every result
+func Replayable() Option {
+	return func(gc *goCancelable) {
+		gc.record = true
+	}
+}
+
+// WithHistory records every delivered result to be returned by History, for
+// auditing or asserting on everything a handler sent. The recorded history
+// grows with every result. Equivalent to Replayable
+func WithHistory() Option {
+	return Replayable()
+}
+
+// WithResultMaxSize replaces results whose size as measured by sizeOf exceeds
+// bytes with an error wrapping ErrResultTooLarge
+func WithResultMaxSize(bytes int, sizeOf func(v interface{}) int) Option {
+	return func(gc *goCancelable) {
+		gc.maxSize = bytes
+		gc.sizeOf = sizeOf
+	}
+}
+
+// WithTimeout cancels the cancelable with context.DeadlineExceeded once d
+// has elapsed since Start
+func WithTimeout(d time.Duration) Option {
+	return func(gc *goCancelable) {
+		gc.timeout = d
+	}
+}
+
+// WithResultGrouping collects results by the key returned from by and sends
+// them as Group batches once flush has elapsed since the first result of the
+// window. Pending groups are sent when the handler returns and dropped on
+// Cancel
+func WithResultGrouping(by func(v interface{}) interface{}, flush time.Duration) Option {
+	return func(gc *goCancelable) {
+		gc.groupBy = by
+		gc.flush = flush
+	}
+}
+
+// WithResultErrorChannel sends error results on ch instead of the result
+// channel. ch is owned by the caller and is not closed on Cancel
+func WithResultErrorChannel(ch chan<- error) Option {
+	return func(gc *goCancelable) {
+		gc.errCh = ch
+	}
+}
+
+// WithBackoffDelivery makes Send retry on a full channel instead of blocking,
+// waiting base before the first retry and doubling the wait up to max on
+// every consecutive stall. A send that doesn't stall resets the wait
+func WithBackoffDelivery(base time.Duration, max time.Duration) Option {
+	return func(gc *goCancelable) {
+		gc.backoffBase = base
+		gc.backoffMax = max
+	}
+}
+
+// WithShutdownContext cancels the cancelable with the context's cause once
+// ctx is done, independently of the context passed to Start. Sharing one
+// shutdown context between cancelables cancels all of them at once
+func WithShutdownContext(ctx context.Context) Option {
+	return func(gc *goCancelable) {
+		gc.watch(ctx)
+	}
+}
+
+// WithTotalOrder sends results in the order Send was called, even when
+// concurrent workers or retries would otherwise deliver them out of order.
+// Results are numbered on entry to Send and held in a reorder buffer until
+// every earlier result was sent
+func WithTotalOrder() Option {
+	return func(gc *goCancelable) {
+		gc.totalOrder = true
+	}
+}
+
+// WithCancelOnNil treats Send(nil) as the handler being done: instead of
+// delivering nil, pending results are flushed and the cancelable is
+// canceled without a cause
+func WithCancelOnNil() Option {
+	return func(gc *goCancelable) {
+		gc.cancelOnNil = true
+	}
+}
+
+// WithDeadlineExtension moves the timeout to d from now on each result sent,
+// so a handler that keeps producing isn't canceled while a stalled one still
+// is. Requires WithTimeout
+func WithDeadlineExtension(d time.Duration) Option {
+	return func(gc *goCancelable) {
+		gc.extension = d
+	}
+}
+
+// WithSourceID sets the ID reported by SourceID, typically the SourceID of
+// the cancelable the results are derived from
+func WithSourceID(id uint64) Option {
+	return func(gc *goCancelable) {
+		gc.sourceID = id
+	}
+}
+
+// WithMergeStrategy sets the order a cancelable created by MergeWith sends
+// the results of its inputs in when several are available. The default is
+// MergeArrival
+func WithMergeStrategy(s MergeStrategy) Option {
+	return func(gc *goCancelable) {
+		gc.merge = s
+	}
+}
+
+// WithRateLimitDrop makes a GoRaceRateLimited cancelable drop the results
+// sent in excess of its rate instead of blocking the sender
+func WithRateLimitDrop() Option {
+	return func(gc *goCancelable) {
+		gc.dropExcess = true
+	}
+}
+
+// WithBuffer makes the channel buffer n results instead of one
+func WithBuffer(n int) Option {
+	return func(gc *goCancelable) {
+		gc.send = make(chan interface{}, n)
+	}
+}
+
+// WithName sets the name identifying the cancelable in logs, warnings and
+// observer callbacks
+func WithName(name string) Option {
+	return func(gc *goCancelable) {
+		gc.name = name
+	}
+}
+
+// WithLogger logs the lifecycle of the cancelable to l: starts and
+// completions at debug level, cancels at info level or warn level with a
+// cause, handler panics at error level and dropped results at debug level.
+// Events carry the name, state and elapsed time. Warnings such as those of
+// WithSendWatchdog are written to l as well. Nothing is logged by default
+func WithLogger(l *slog.Logger) Option {
+	return func(gc *goCancelable) {
+		gc.logger = l
+	}
+}
+
+// WithObserver notifies o of the cancelable's lifecycle
+func WithObserver(o Observer) Option {
+	return func(gc *goCancelable) {
+		gc.observer = o
+	}
+}
+
+// WithLeakCheck logs a warning if the cancelable is garbage collected while
+// started and not canceled, see GoRaceWithLeakCheck. Only applies to
+// constructors returning a GoCancelable
+func WithLeakCheck() Option {
+	return func(gc *goCancelable) {
+		gc.leakCheck = true
+	}
+}
+
+// WithSendWatchdog logs a warning with the stack trace of the sender when a
+// Send blocks for longer than d, which usually means nobody drains the
+// channel. The Send keeps waiting
+func WithSendWatchdog(d time.Duration) Option {
+	return func(gc *goCancelable) {
+		gc.watchdog = d
+	}
+}
+
+// WithLossySend makes Send best-effort: it never blocks nor panics, results
+// that can't be sent right away are dropped and counted by Dropped
+func WithLossySend() Option {
+	return func(gc *goCancelable) {
+		gc.lossy = true
+	}
+}
+
+// WithRegistry tracks the cancelable in r until it is canceled
+func WithRegistry(r *Registry) Option {
+	return func(gc *goCancelable) {
+		gc.registry = r
+	}
+}
+
+// WithErrorStop cancels the cancelable with the first error result once it
+// is sent, so consumers receive the error last and Cause returns it
+func WithErrorStop() Option {
+	return func(gc *goCancelable) {
+		gc.errorStop = true
+	}
+}
+
+// WithReplay makes Subscribe call late subscribers with the last n sent
+// results before the live ones, or every result sent so far if there were
+// fewer than n
+func WithReplay(n int) Option {
+	return func(gc *goCancelable) {
+		gc.replayN = n
+	}
+}
+
+// WithSpillover makes Send queue results while the channel is full instead
+// of blocking, a pump moves them to the channel in order as it frees up. A
+// max above 0 bounds the queue, policy decides what Send does once it's full.
+// Queued results are sent before the cancelable completes and dropped on
+// Cancel, counting them in Dropped
+func WithSpillover(max int, policy SpillPolicy) Option {
+	return func(gc *goCancelable) {
+		gc.spill = true
+		gc.spillMax = max
+		gc.spillPolicy = policy
+	}
+}
+
+// WithFinalizer calls fn once the cancelable is canceled or completes and
+// sends its value as the last result before the channel closes, so consumers
+// get a defined terminal value such as the partial progress. fn is called
+// with the cancelable locked and must not call its methods. A completed
+// handler's value waits for room like Send. On cancel it is only delivered if
+// the channel has room or a consumer is waiting, otherwise it is dropped;
+// pending results are never discarded for it
+func WithFinalizer(fn func() interface{}) Option {
+	return func(gc *goCancelable) {
+		gc.finalizer = fn
+	}
+}
@@ -0,0 +1,623 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceWithResultTimestamp() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 5; i++ {
+			cancelable.Send(i)
+		}
+	}, WithResultTimestamp())
+	start := time.Now()
+	cancelable.Start(context.Background())
+
+	var last time.Time
+	i := 0
+	for result := range cancelable.Receive() {
+		stamped, ok := result.(Timestamped)
+		suite.Require().True(ok, "<-cancelable.Receive() should be Timestamped")
+		suite.Equal(i, stamped.Value, "Timestamped.Value should be the sent value")
+		suite.WithinDuration(start, stamped.At, time.Second, "Timestamped.At should be close to the send time")
+		suite.False(stamped.At.Before(last), "Timestamped.At should be monotonic")
+		last = stamped.At
+		i++
+	}
+
+	suite.Equal(5, i, "all values should be received")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithResultSink() {
+	var mu sync.Mutex
+	var results []interface{}
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	}, WithResultSink(func(v interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, v)
+		return nil
+	}))
+	cancelable.Start(context.Background())
+
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(results) == 3
+	}, time.Second, 10*time.Millisecond, "sink should receive every result")
+	suite.Equal([]interface{}{0, 1, 2}, results)
+	suite.Nil(cancelable.Cause(), "cancelable.Cause() should be nil")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithResultSinkError() {
+	failure := errors.New("sink failed")
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 50; i++ {
+			cancelable.Send(i)
+		}
+		<-ctx.Done()
+	}, WithResultSink(func(v interface{}) error {
+		if v == 1 {
+			return failure
+		}
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelable.Start(ctx)
+
+	suite.Eventually(cancelable.IsCanceled, time.Second, 10*time.Millisecond, "sink error should cancel the cancelable")
+	suite.Equal(failure, cancelable.Cause(), "cancelable.Cause() should be the sink error")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithHistory() {
+	handler := func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	}
+	cancelable := GoRace(handler, WithHistory())
+	cancelable.Start(context.Background())
+	for range cancelable.Receive() {
+	}
+
+	history := cancelable.History()
+	suite.Equal([]interface{}{0, 1, 2}, history, "every delivered result should be recorded")
+	history[0] = "modified"
+	suite.Equal(0, cancelable.History()[0], "cancelable.History() should return a copy")
+
+	unrecorded := GoRace(handler)
+	unrecorded.Start(context.Background())
+	for range unrecorded.Receive() {
+	}
+	suite.Nil(unrecorded.History(), "results should only be recorded with WithHistory")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceReplayable() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 5; i++ {
+			cancelable.Send(i)
+		}
+	}, Replayable())
+	cancelable.Start(context.Background())
+
+	var received []interface{}
+	for result := range cancelable.Receive() {
+		received = append(received, result)
+	}
+
+	replay := make(chan interface{}, len(received))
+	cancelable.Replay(replay)
+	close(replay)
+	var replayed []interface{}
+	for result := range replay {
+		replayed = append(replayed, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2, 3, 4}, received)
+	suite.Equal(received, replayed, "cancelable.Replay() should match the delivered sequence")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithResultMaxSize() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send("small")
+		cancelable.Send("far too large")
+	}, WithResultMaxSize(8, func(v interface{}) int {
+		return len(v.(string))
+	}))
+	cancelable.Start(context.Background())
+
+	suite.Equal("small", <-cancelable.Receive(), "small results should be delivered")
+	err, ok := (<-cancelable.Receive()).(error)
+	suite.Require().True(ok, "oversized results should be replaced by an error")
+	suite.ErrorIs(err, ErrResultTooLarge)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithTimeout() {
+	stop := make(chan struct{})
+	defer close(stop)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-stop
+	}, WithTimeout(50*time.Millisecond))
+
+	_, ok := cancelable.Deadline()
+	suite.False(ok, "cancelable.Deadline() should not be set before Start")
+
+	start := time.Now()
+	cancelable.Start(context.Background())
+	deadline, ok := cancelable.Deadline()
+	suite.True(ok, "cancelable.Deadline() should be set after Start")
+	suite.WithinDuration(start.Add(50*time.Millisecond), deadline, 10*time.Millisecond)
+
+	suite.Eventually(cancelable.IsCanceled, time.Second, 10*time.Millisecond, "timeout should cancel the cancelable")
+	suite.Equal(context.DeadlineExceeded, cancelable.Cause(), "cancelable.Cause() should be context.DeadlineExceeded")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRemaining() {
+	remaining := make(chan time.Duration, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		remaining <- cancelable.Remaining()
+	}, WithTimeout(time.Minute))
+	suite.Equal(NoDeadline, cancelable.Remaining(), "cancelable.Remaining() should be NoDeadline before Start")
+	cancelable.Start(context.Background())
+
+	left := <-remaining
+	suite.True(left > 59*time.Second && left <= time.Minute, "cancelable.Remaining() should be close to the timeout, got %v", left)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceDefaultTimeout() {
+	stop := make(chan struct{})
+	defer close(stop)
+	SetDefaultTimeout(20 * time.Millisecond)
+	defaulted := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-stop
+	})
+	explicit := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-stop
+	}, WithTimeout(time.Hour))
+	SetDefaultTimeout(0)
+	unlimited := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-stop
+	})
+	defer unlimited.Cancel()
+	defer explicit.Cancel()
+
+	defaulted.Start(context.Background())
+	explicit.Start(context.Background())
+	unlimited.Start(context.Background())
+
+	suite.Eventually(defaulted.IsCanceled, time.Second, 10*time.Millisecond, "the default timeout should cancel the cancelable")
+	suite.Equal(context.DeadlineExceeded, defaulted.Cause(), "cancelable.Cause() should be context.DeadlineExceeded")
+	suite.False(explicit.IsCanceled(), "an explicit timeout should take precedence over the default")
+	_, ok := unlimited.Deadline()
+	suite.False(ok, "cancelables constructed without a default timeout should have no deadline")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceDeadlineFromContext() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {}, WithTimeout(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cancelable.Start(ctx)
+
+	ctxDeadline, _ := ctx.Deadline()
+	deadline, ok := cancelable.Deadline()
+	suite.True(ok, "cancelable.Deadline() should be set")
+	suite.Equal(ctxDeadline, deadline, "cancelable.Deadline() should be the earlier context deadline")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithResultGrouping() {
+	received := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for _, v := range []string{"a1", "b1", "a2", "b2", "a3"} {
+			cancelable.Send(v)
+		}
+		<-received
+		cancelable.Send("c1")
+	}, WithResultGrouping(func(v interface{}) interface{} {
+		return v.(string)[:1]
+	}, 50*time.Millisecond))
+	cancelable.Start(context.Background())
+
+	suite.Equal(Group{Key: "a", Values: []interface{}{"a1", "a2", "a3"}}, <-cancelable.Receive())
+	suite.Equal(Group{Key: "b", Values: []interface{}{"b1", "b2"}}, <-cancelable.Receive())
+	close(received)
+
+	var groups []interface{}
+	for result := range cancelable.Receive() {
+		groups = append(groups, result)
+	}
+	suite.Equal([]interface{}{Group{Key: "c", Values: []interface{}{"c1"}}}, groups, "pending groups should be sent when the handler returns")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithResultErrorChannel() {
+	failure := errors.New("request failed")
+	errs := make(chan error, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		cancelable.Send(failure)
+		cancelable.Send(false)
+	}, WithResultErrorChannel(errs))
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{true, false}, results, "only non-error results should be received")
+	suite.Equal(failure, <-errs, "errors should be sent on the error channel")
+	select {
+	case errs <- nil:
+	default:
+		suite.Fail("the error channel should not be closed")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithBackoffDelivery() {
+	drained := make(chan struct{})
+	delays := make(chan time.Duration, 2)
+	elapsed := make(chan time.Duration, 1)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		gc := cancelable.(*goCancelable)
+		backoffDelay := func() time.Duration {
+			gc.mu.Lock()
+			defer gc.mu.Unlock()
+			return gc.backoffDelay
+		}
+		cancelable.Send(0)
+		start := time.Now()
+		cancelable.Send(1) // stalls until the consumer reads
+		elapsed <- time.Since(start)
+		delays <- backoffDelay()
+		<-drained
+		cancelable.Send(2)
+		delays <- backoffDelay()
+	}, WithBackoffDelivery(10*time.Millisecond, 40*time.Millisecond))
+	cancelable.Start(context.Background())
+
+	time.Sleep(100 * time.Millisecond)
+	suite.Equal(0, <-cancelable.Receive())
+	suite.Equal(1, <-cancelable.Receive())
+	close(drained)
+	suite.Equal(2, <-cancelable.Receive())
+
+	suite.GreaterOrEqual(<-elapsed, 100*time.Millisecond, "a stalled send should wait for the consumer")
+	suite.Equal(40*time.Millisecond, <-delays, "the delay should grow up to max while stalled")
+	suite.Equal(time.Duration(0), <-delays, "a send that doesn't stall should reset the delay")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithShutdownContext() {
+	shutdown, cancel := context.WithCancel(context.Background())
+	var cancelables []GoCancelable
+	for i := 0; i < 3; i++ {
+		cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+			<-ctx.Done()
+		}, WithShutdownContext(shutdown))
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		cancelables = append(cancelables, cancelable.Start(ctx))
+	}
+	idle := GoRace(func(ctx context.Context, cancelable GoCancelable) {}, WithShutdownContext(shutdown))
+
+	cancel()
+
+	for _, cancelable := range append(cancelables, idle) {
+		suite.Eventually(cancelable.IsCanceled, time.Second, 10*time.Millisecond, "shutdown should cancel every cancelable")
+		suite.Equal(context.Canceled, cancelable.Cause(), "cancelable.Cause() should be the shutdown cause")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceExtendDeadline() {
+	cancelable := GoRaceTimeout(50*time.Millisecond, func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			<-time.After(30 * time.Millisecond)
+			cancelable.ExtendDeadline(50 * time.Millisecond)
+		}
+		cancelable.Send(true)
+	})
+	cancelable.Start(context.Background())
+
+	suite.Equal(true, <-cancelable.Receive(), "extending the deadline should let the handler finish")
+	suite.Nil(cancelable.Cause(), "cancelable.Cause() should be nil")
+
+	idle := GoRace(func(ctx context.Context, cancelable GoCancelable) {})
+	idle.ExtendDeadline(time.Second)
+	_, ok := idle.Deadline()
+	suite.False(ok, "cancelable.ExtendDeadline() should do nothing without a timeout")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithCancelOnNil() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2)
+		cancelable.Send(nil)
+		cancelable.Send(3)
+	}, WithCancelOnNil())
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{1, 2}, results, "nil should end the results instead of being received")
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+	suite.Nil(cancelable.Cause(), "cancelable.Cause() should be nil")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithDeadlineExtension() {
+	sending := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 5; i++ {
+			<-time.After(20 * time.Millisecond)
+			cancelable.Send(i)
+		}
+	}, WithTimeout(50*time.Millisecond), WithDeadlineExtension(50*time.Millisecond))
+	stalled := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(0)
+		<-ctx.Done()
+	}, WithTimeout(50*time.Millisecond), WithDeadlineExtension(50*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	sending.Start(ctx)
+	stalled.Start(ctx)
+
+	var results []interface{}
+	for result := range sending.Receive() {
+		results = append(results, result)
+	}
+	<-stalled.Receive()
+
+	suite.Equal([]interface{}{0, 1, 2, 3, 4}, results, "a sending handler should outlive the initial deadline")
+	suite.Nil(sending.Cause(), "sending.Cause() should be nil")
+	suite.Eventually(stalled.IsCanceled, 500*time.Millisecond, 10*time.Millisecond, "a stalled handler should be canceled")
+	suite.Equal(context.DeadlineExceeded, stalled.Cause())
+}
+
+// Records the lifecycle of cancelables
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *recordingObserver) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) Started(c GoCancelable) {
+	o.record("started " + c.Name())
+}
+
+func (o *recordingObserver) Sent(c GoCancelable, result interface{}) {
+	o.record(fmt.Sprintf("sent %v", result))
+}
+
+func (o *recordingObserver) Canceled(c GoCancelable, cause error) {
+	o.record(fmt.Sprintf("canceled %v", cause))
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithOptions() {
+	observer := &recordingObserver{}
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 3; i++ {
+			cancelable.Send(i)
+		}
+	}, WithBuffer(3), WithName("fetch"), WithObserver(observer))
+
+	suite.Equal("fetch", cancelable.Name())
+	cancelable.Run(context.Background())
+
+	suite.Equal(3, cancelable.Pending(), "WithBuffer should buffer every result")
+	suite.Equal([]string{"started fetch", "sent 0", "sent 1", "sent 2", "canceled <nil>"}, observer.events)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithLeakCheck() {
+	_, ok := GoRace(func(ctx context.Context, cancelable GoCancelable) {}, WithLeakCheck()).(*leakChecked)
+	suite.True(ok, "WithLeakCheck should return a leak checked cancelable")
+	_, ok = GoRaceBatch(2, time.Second, func(ctx context.Context, cancelable GoCancelable) {}, WithLeakCheck()).(*leakChecked)
+	suite.True(ok, "WithLeakCheck should apply to other constructors")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithSendWatchdog() {
+	warnings := make(chan string, 2)
+	warnf = func(format string, v ...interface{}) {
+		warnings <- fmt.Sprintf(format, v...)
+	}
+	defer func() { warnf = log.Printf }()
+
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2) // blocks until received
+	}, WithName("stuck"), WithSendWatchdog(10*time.Millisecond))
+	cancelable.Start(context.Background())
+
+	select {
+	case warning := <-warnings:
+		suite.Contains(warning, `"stuck"`, "the warning should name the cancelable")
+		suite.Contains(warning, "TestGoRaceWithSendWatchdog", "the warning should include the sender's stack")
+	case <-time.After(time.Second):
+		suite.Fail("a blocked Send should be reported")
+	}
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{1, 2}, results, "the Send should keep waiting after the warning")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithLossySend() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 10; i++ {
+			cancelable.Send(i)
+		}
+		cancelable.Cancel()
+		cancelable.Send(10)
+	}, WithLossySend())
+
+	cancelable.Run(context.Background())
+
+	suite.Equal(0, <-cancelable.Receive(), "the first result should fit in the buffer")
+	suite.Equal(uint64(10), cancelable.Dropped(), "results sent to a full or canceled cancelable should be dropped")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithErrorStop() {
+	failure := errors.New("request failed")
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(failure)
+		cancelable.Send(2)
+	}, WithErrorStop())
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{1, failure}, results, "the error should be the last result")
+	suite.Equal(failure, cancelable.Cause())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithReplay() {
+	next := make(chan int)
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := range next {
+			cancelable.Send(i)
+		}
+	}, WithReplay(3))
+	cancelable.Start(context.Background())
+	for i := 0; i < 5; i++ {
+		next <- i
+		<-cancelable.Receive()
+	}
+
+	results := make(chan interface{}, 10)
+	cancelable.Subscribe(context.Background(), func(result interface{}) {
+		results <- result
+	})
+	next <- 5
+	close(next)
+
+	for _, expected := range []interface{}{2, 3, 4, 5} {
+		suite.Equal(expected, <-results, "late subscribers should get the last results replayed first")
+	}
+
+	short := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(0)
+	}, WithReplay(3))
+	short.Start(context.Background())
+	for range short.Receive() {
+	}
+	replayed := make(chan interface{}, 10)
+	short.Subscribe(context.Background(), func(result interface{}) {
+		replayed <- result
+	})
+	suite.Equal(0, <-replayed, "every result should be replayed if there were fewer than n")
+	select {
+	case result := <-replayed:
+		suite.Fail("only sent results should be replayed", "got %v", result)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithReplayBuffered() {
+	release := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2)
+		<-release
+	}, WithReplay(3), WithBuffer(2))
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool { return cancelable.Pending() == 2 }, time.Second, time.Millisecond)
+
+	results := make(chan interface{}, 10)
+	cancelable.Subscribe(context.Background(), func(result interface{}) {
+		results <- result
+	})
+	close(release)
+	<-cancelable.Done()
+
+	var received []interface{}
+	for len(received) < 2 {
+		received = append(received, <-results)
+	}
+	select {
+	case result := <-results:
+		received = append(received, result)
+	case <-time.After(10 * time.Millisecond):
+	}
+	suite.Equal([]interface{}{1, 2}, received, "buffered results should only be received live")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithFinalizer() {
+	var progress atomic.Int64
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		progress.Store(1)
+		cancelable.Send(1)
+		<-ctx.Done()
+	}, WithBuffer(2), WithFinalizer(func() interface{} {
+		return fmt.Sprintf("stopped at %d", progress.Load())
+	}))
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool { return cancelable.Pending() == 1 }, time.Second, time.Millisecond)
+	cancelable.Cancel()
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{1, "stopped at 1"}, results, "the final value should be the last result")
+	suite.Equal("stopped at 1", cancelable.LastResult())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithFinalizerCompleted() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send("real result")
+	}, WithFinalizer(func() interface{} {
+		return "final"
+	}))
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{"real result", "final"}, results, "the final value should wait for room")
+	suite.Equal(uint64(0), cancelable.Dropped())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithFinalizerFullChannel() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send("real result")
+		<-ctx.Done()
+	}, WithFinalizer(func() interface{} {
+		return "final"
+	}))
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool { return cancelable.Pending() == 1 }, time.Second, time.Millisecond)
+	cancelable.Cancel()
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{"real result"}, results, "pending results should not make room for the final value")
+	suite.Equal(uint64(1), cancelable.Dropped())
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceTimeoutKeepsOptions() {
+	opts := make([]Option, 1, 2)
+	opts[0] = WithName("timed")
+	GoRaceTimeout(time.Second, func(ctx context.Context, cancelable GoCancelable) {}, opts...)
+	suite.Nil(opts[:2][1], "GoRaceTimeout should not write into the caller's options")
+}
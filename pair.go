@@ -0,0 +1,53 @@
+package gorace
+
+import "context"
+
+// Pair is the typed pair delivered by GoRace2 cancelables
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// GoCancelable2 contract. Equivalent to GoCancelable except the handler
+// sends two values at once and they are received as a typed Pair
+type GoCancelable2[A, B any] interface {
+	// Cancel closes the internal channel and returns true. If the
+	// cancelable is already canceled this returns false
+	Cancel() bool
+	// Send sends both values as one Pair to channel listeners
+	Send(a A, b B)
+	// Receive returns the pair channel
+	Receive() <-chan Pair[A, B]
+	// Start runs the userdefined handler func. The specified context is
+	// passed through to the handler func
+	Start(ctx context.Context) GoCancelable2[A, B]
+	// StartBackground starts the cancelable on a goroutine
+	StartBackground(ctx context.Context) GoCancelable2[A, B]
+	// LastResult returns the last pair sent successfully on the channel
+	LastResult() Pair[A, B]
+	// Cause returns the error the cancelable was canceled with, if any
+	Cause() error
+	// IsCanceled returns true if the cancelable is canceled otherwise
+	// returns false
+	IsCanceled() bool
+}
+
+// GoRace2 creates a cancelable whose handler sends pairs of values, such as the (value, error) results of
+// a call, which consumers receive as a Pair without a type switch
+func GoRace2[A, B any](handler func(ctx context.Context, cancelable GoCancelable2[A, B]), opts ...Option) GoCancelable2[A, B] {
+	pc := &goCancelable2[A, B]{}
+	pc.wrapper = newWrapper[Pair[A, B], GoCancelable2[A, B]](pc, func(ctx context.Context, _ GoCancelable) {
+		handler(ctx, pc)
+	}, opts...)
+	return pc
+}
+
+// Implementation of GoCancelable2 on top of a regular cancelable
+type goCancelable2[A, B any] struct {
+	*wrapper[Pair[A, B], GoCancelable2[A, B]]
+}
+
+// Send sends the values wrapped in a Pair
+func (pc *goCancelable2[A, B]) Send(a A, b B) {
+	pc.gc.Send(Pair[A, B]{First: a, Second: b})
+}
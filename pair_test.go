@@ -0,0 +1,40 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRace2() {
+	failure := errors.New("request failed")
+	cancelable := GoRace2(func(ctx context.Context, cancelable GoCancelable2[bool, error]) {
+		cancelable.Send(work(ctx), nil)
+		cancelable.Send(false, failure)
+	})
+	cancelable.Start(context.Background())
+
+	var results []Pair[bool, error]
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]Pair[bool, error]{{First: true}, {Second: failure}}, results)
+	suite.Equal(Pair[bool, error]{Second: failure}, cancelable.LastResult(), "cancelable.LastResult() should be the last Pair")
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRace2UnexpectedType() {
+	cancelable := GoRace2(func(ctx context.Context, cancelable GoCancelable2[int, error]) {
+		cancelable.Send(1, nil)
+		<-ctx.Done()
+	}, WithResultTimestamp())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cancelable.Start(ctx)
+
+	for range cancelable.Receive() {
+		suite.Fail("a Timestamped value should not be received as a Pair")
+	}
+	suite.ErrorIs(cancelable.Cause(), ErrUnexpectedType)
+}
@@ -0,0 +1,236 @@
+package gorace
+
+import "container/heap"
+
+// A result waiting in the priority queue
+type priorityItem struct {
+	result   interface{}
+	priority int
+	seq      uint64
+	index    int
+	prepared bool // Already passed through prepare, as spilled results are
+}
+
+// Max-heap of pending results by priority, results of equal priority keep the order they were sent in
+type priorityQueue []*priorityItem
+
+func (q priorityQueue) Len() int { return len(q) }
+
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q priorityQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+
+func (q *priorityQueue) Push(x interface{}) {
+	item := x.(*priorityItem)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// SendPriority queues the result to be sent ahead of every queued result of lower priority. Results are
+// moved from the queue to the channel by a pump goroutine as the channel frees up, so this doesn't block.
+// Queued results are sent before the cancelable completes and dropped on cancel, counting them in Dropped
+// like results sent after the cancel
+func (gc *goCancelable) SendPriority(result interface{}, priority int) {
+	if gc.canceled.Load() {
+		gc.dropped.Add(1)
+		return
+	}
+	gc.enqueue(result, priority, false)
+}
+
+// Queues the result for the pump, starting the pump on first use
+func (gc *goCancelable) enqueue(result interface{}, priority int, prepared bool) {
+	gc.prioOnce.Do(func() {
+		gc.prioWake = make(chan struct{}, 1)
+		gc.prioRoom = make(chan struct{}, 1)
+		gc.prioStop = make(chan struct{})
+		gc.prioDone = make(chan struct{})
+		go gc.pump()
+	})
+	gc.prioMu.Lock()
+	if gc.canceled.Load() {
+		// Too late for the pump, which already discarded the queue or is about to
+		gc.prioMu.Unlock()
+		gc.dropped.Add(1)
+		return
+	}
+	gc.prioSeq++
+	heap.Push(&gc.prio, &priorityItem{result: result, priority: priority, seq: gc.prioSeq, prepared: prepared})
+	gc.prioMu.Unlock()
+	signal(gc.prioWake)
+}
+
+// Returns the number of queued results, including the one the pump is sending
+func (gc *goCancelable) queued() int {
+	gc.prioMu.Lock()
+	defer gc.prioMu.Unlock()
+	return len(gc.prio) + gc.prioHeld
+}
+
+// Takes the queued result with the highest priority off the queue for the pump, or returns nil
+func (gc *goCancelable) takePriority() *priorityItem {
+	gc.prioMu.Lock()
+	defer gc.prioMu.Unlock()
+	if len(gc.prio) == 0 {
+		return nil
+	}
+	gc.prioHeld++
+	return heap.Pop(&gc.prio).(*priorityItem)
+}
+
+// Hands a result taken by the pump back, to the queue if it wasn't sent or else by making room for another
+func (gc *goCancelable) releasePriority(item *priorityItem, sent bool) {
+	gc.prioMu.Lock()
+	gc.prioHeld--
+	if !sent {
+		heap.Push(&gc.prio, item)
+	}
+	gc.prioMu.Unlock()
+	if sent {
+		signal(gc.prioRoom)
+	}
+}
+
+// Returns the queued result with the highest priority or nil
+func (gc *goCancelable) nextPriority() *priorityItem {
+	gc.prioMu.Lock()
+	defer gc.prioMu.Unlock()
+	if len(gc.prio) == 0 {
+		return nil
+	}
+	return gc.prio[0]
+}
+
+// Drops every queued result, counting them in Dropped
+func (gc *goCancelable) discardPriority() {
+	gc.prioMu.Lock()
+	defer gc.prioMu.Unlock()
+	gc.dropped.Add(uint64(len(gc.prio)))
+	gc.prio = nil
+}
+
+// Removes a result from the priority queue
+func (gc *goCancelable) removePriority(item *priorityItem) {
+	gc.prioMu.Lock()
+	defer gc.prioMu.Unlock()
+	heap.Remove(&gc.prio, item.index)
+}
+
+// Returns the queued result ready to be sent
+func (gc *goCancelable) dequeued(item *priorityItem) interface{} {
+	if item.prepared {
+		return item.result
+	}
+	return gc.prepare(item.result)
+}
+
+// Sends the queued results in priority order until stopped by flushPriority or canceled. Results still
+// queued on cancel are dropped
+func (gc *goCancelable) pump() {
+	defer close(gc.prioDone)
+	defer func() {
+		if gc.canceled.Load() {
+			gc.discardPriority()
+		}
+	}()
+	for {
+		item := gc.takePriority()
+		if item == nil {
+			select {
+			case <-gc.prioWake:
+				continue
+			case <-gc.prioStop:
+				return
+			case <-gc.quit:
+				return
+			}
+		}
+		if !gc.pumpOne(item) {
+			return
+		}
+	}
+}
+
+// Sends a result taken off the queue once the channel has room. Only the read lock is held while waiting,
+// so sends and cancels aren't held up. When another result is queued meanwhile, this one goes back to the
+// queue in case the new one has a higher priority. Returns false once the pump has to stop
+func (gc *goCancelable) pumpOne(item *priorityItem) bool {
+	result := gc.dequeued(item)
+	gc.closing.RLock()
+	if gc.canceled.Load() {
+		gc.closing.RUnlock()
+		gc.releasePriority(item, false)
+		return false
+	}
+	select {
+	case gc.send <- result:
+		gc.closing.RUnlock()
+		gc.mu.Lock()
+		gc.delivered(result)
+		gc.mu.Unlock()
+		gc.releasePriority(item, true)
+		return true
+	case <-gc.prioWake:
+		gc.closing.RUnlock()
+		gc.releasePriority(item, false)
+		return true
+	case <-gc.prioStop:
+		gc.closing.RUnlock()
+		gc.releasePriority(item, false)
+		return false
+	case <-gc.quit:
+		gc.closing.RUnlock()
+		gc.releasePriority(item, false)
+		return false
+	}
+}
+
+// Stops the pump and sends every queued result in priority order, or drops what is left once canceled
+func (gc *goCancelable) flushPriority() {
+	gc.prioOnce.Do(func() {}) // Keeps the pump from starting from now on
+	if gc.prioStop != nil {
+		gc.prioMu.Lock()
+		select {
+		case <-gc.prioStop:
+		default:
+			close(gc.prioStop)
+		}
+		gc.prioMu.Unlock()
+		<-gc.prioDone
+	}
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	for item := gc.nextPriority(); item != nil && !gc.canceled.Load(); item = gc.nextPriority() {
+		gc.removePriority(item)
+		result := gc.dequeued(item)
+		if !gc.push(result) {
+			gc.dropped.Add(1)
+			continue
+		}
+		gc.delivered(result)
+	}
+	gc.discardPriority()
+}
+
+// Wakes up a goroutine waiting on ch, unless a wake up is pending already
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
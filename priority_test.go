@@ -0,0 +1,48 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceSendPriority() {
+	queued := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.SendPriority("low1", 0)
+		cancelable.SendPriority("low2", 0)
+		cancelable.SendPriority("low3", 0)
+		cancelable.SendPriority("high", 10)
+		close(queued)
+	})
+	cancelable.Start(context.Background())
+	<-queued
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Len(results, 4, "every queued result should be sent")
+	suite.Contains(results[:2], "high", "the high priority result should preempt the queued ones")
+	suite.Equal([]interface{}{"low2", "low3"}, results[2:], "results of equal priority should keep their order")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceSendPriorityCancel() {
+	queued := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 4; i++ {
+			cancelable.SendPriority(i, i)
+		}
+		close(queued)
+		<-ctx.Done()
+	}, WithBuffer(0))
+	cancelable.Start(context.Background())
+	<-queued
+	cancelable.Cancel()
+	cancelable.SendPriority(4, 0)
+
+	suite.Eventually(func() bool {
+		delivered, dropped := cancelable.Stats()
+		return delivered == 0 && dropped == 5
+	}, time.Second, time.Millisecond, "queued results and results sent after the cancel should be dropped")
+}
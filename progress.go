@@ -0,0 +1,63 @@
+package gorace
+
+import (
+	"context"
+	"sync"
+)
+
+// GoProgressCancelable contract. A cancelable that reports progress on a
+// separate channel next to its results
+type GoProgressCancelable interface {
+	GoCancelable
+	// Progress returns the progress channel. It is closed when the
+	// cancelable is canceled
+	Progress() <-chan float64
+	// ReportProgress publishes p on the progress channel without blocking.
+	// Updates nobody received yet are replaced by newer ones
+	ReportProgress(p float64)
+}
+
+// GoRaceProgress creates a cancelable whose handler can report progress separately from its results
+func GoRaceProgress(handler func(ctx context.Context, cancelable GoProgressCancelable), opts ...Option) GoProgressCancelable {
+	pc := &goProgressCancelable{progress: make(chan float64, 1)}
+	pc.goCancelable = newGoCancelable(func(ctx context.Context, _ GoCancelable) {
+		handler(ctx, pc)
+	}, opts...)
+	pc.afterCancel(pc.closeProgress)
+	return pc
+}
+
+// Implementation of GoProgressCancelable on top of a regular cancelable
+type goProgressCancelable struct {
+	*goCancelable
+	progress chan float64
+	closed   bool
+	pmu      sync.Mutex // Separate from the cancelable's lock so progress never waits on a blocked Send
+}
+
+// Progress returns the progress channel
+func (pc *goProgressCancelable) Progress() <-chan float64 {
+	return pc.progress
+}
+
+// ReportProgress replaces any unreceived progress update with p. Does nothing once canceled
+func (pc *goProgressCancelable) ReportProgress(p float64) {
+	pc.pmu.Lock()
+	defer pc.pmu.Unlock()
+	if pc.closed {
+		return
+	}
+	select {
+	case <-pc.progress:
+	default:
+	}
+	pc.progress <- p // the buffer is empty and only written under the lock, this can't block
+}
+
+// Closes the progress channel
+func (pc *goProgressCancelable) closeProgress() {
+	pc.pmu.Lock()
+	defer pc.pmu.Unlock()
+	pc.closed = true
+	close(pc.progress)
+}
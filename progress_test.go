@@ -0,0 +1,25 @@
+package gorace
+
+import "context"
+
+func (suite *GoRaceTestSuite) TestGoRaceProgress() {
+	reported := make(chan struct{})
+	cancelable := GoRaceProgress(func(ctx context.Context, cancelable GoProgressCancelable) {
+		// Nobody listens to these, they must not block
+		for i := 0; i < 10; i++ {
+			cancelable.ReportProgress(float64(i) / 10)
+		}
+		cancelable.ReportProgress(0.5)
+		close(reported)
+		cancelable.Send(work(ctx))
+	})
+	cancelable.Start(context.Background())
+	<-reported
+
+	suite.Equal(0.5, <-cancelable.Progress(), "the latest progress update should be kept")
+	suite.Equal(true, <-cancelable.Receive(), "results should be unaffected by progress")
+	for range cancelable.Receive() {
+	}
+	_, ok := <-cancelable.Progress()
+	suite.False(ok, "cancelable.Progress() should be closed on cancel")
+}
@@ -0,0 +1,70 @@
+package gorace
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// GoRaceRateLimited creates a cancelable delivering at most perSecond results per second. Excess sends
+// block until a token is available, or are dropped with WithRateLimitDrop. A send waiting for a token
+// returns as soon as the cancelable is canceled
+func GoRaceRateLimited(perSecond float64, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	gc := newGoCancelable(handler, opts...)
+	if perSecond > 0 {
+		gc.limiter = &tokenBucket{rate: perSecond, tokens: 1, last: time.Now()}
+	}
+	return gc.handle()
+}
+
+// Token bucket holding up to one token, refilled at rate tokens per second
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// Takes a token and returns how long to wait until it's available. Without blocking, nothing is taken
+// and ok is false if no token is available right away
+func (b *tokenBucket) reserve(block bool) (wait time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > 1 {
+		b.tokens = 1
+	}
+	b.last = now
+	if b.tokens < 1 && !block {
+		return 0, false
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, true
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), true
+}
+
+// Waits for a token of the rate limiter. Returns false if the send should be skipped because the token
+// isn't available and excess sends are dropped, or the cancelable was canceled while waiting, counting the
+// result as dropped. Must be called without holding locks so Cancel isn't held up
+func (gc *goCancelable) throttle() bool {
+	wait, ok := gc.limiter.reserve(!gc.dropExcess)
+	if !ok {
+		gc.dropped.Add(1)
+		return false
+	}
+	if wait <= 0 {
+		return true
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-gc.quit:
+		gc.dropped.Add(1)
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceRateLimited() {
+	cancelable := GoRaceRateLimited(50, func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 6; i++ {
+			cancelable.Send(i)
+		}
+	})
+	begin := time.Now()
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2, 3, 4, 5}, results)
+	suite.GreaterOrEqual(time.Since(begin), 90*time.Millisecond, "sends should be spaced by the rate")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRateLimitedDrop() {
+	cancelable := GoRaceRateLimited(1, func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 10; i++ {
+			cancelable.Send(i)
+		}
+	}, WithRateLimitDrop())
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0}, results, "excess sends should be dropped")
+	_, dropped := cancelable.Stats()
+	suite.Equal(int64(9), dropped, "excess sends should be counted as dropped")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRateLimitedCancel() {
+	returned := make(chan struct{})
+	cancelable := GoRaceRateLimited(0.01, func(ctx context.Context, cancelable GoCancelable) {
+		defer close(returned)
+		cancelable.Send(0)
+		cancelable.Send(1) // waits for a token
+	})
+	cancelable.Start(context.Background())
+	suite.Equal(0, <-cancelable.Receive())
+
+	cancelable.Cancel()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		suite.Fail("a send waiting for a token should return on Cancel")
+	}
+	suite.Equal(uint64(1), cancelable.Dropped(), "the abandoned send should be counted as dropped")
+}
@@ -0,0 +1,25 @@
+package gorace
+
+// Adds a result to the replay ring buffer, overwriting the oldest one once full. Requires locks prior to
+// this method call to remain concurrency-safe.
+func (gc *goCancelable) remember(result interface{}) {
+	if len(gc.replay) < gc.replayN {
+		gc.replay = append(gc.replay, result)
+		return
+	}
+	gc.replay[gc.replayNext] = result
+	gc.replayNext = (gc.replayNext + 1) % gc.replayN
+}
+
+// Returns the results in the replay ring buffer, oldest first. The newest ones still buffered in the
+// channel are left out since the subscriber receives them live. Requires locks prior to this method call
+// to remain concurrency-safe.
+func (gc *goCancelable) recent() []interface{} {
+	recent := make([]interface{}, 0, len(gc.replay))
+	recent = append(recent, gc.replay[gc.replayNext:]...)
+	recent = append(recent, gc.replay[:gc.replayNext]...)
+	if buffered := len(gc.send); buffered < len(recent) {
+		return recent[:len(recent)-buffered]
+	}
+	return nil
+}
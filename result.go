@@ -0,0 +1,61 @@
+package gorace
+
+import "context"
+
+// Result is the envelope delivered by GoRaceResult cancelables. Err is set
+// for results sent with SendError
+type Result struct {
+	Value interface{}
+	Err   error
+}
+
+// GoResultCancelable contract. Equivalent to GoCancelable except results and
+// errors are sent separately and delivered as Result envelopes
+type GoResultCancelable interface {
+	// Cancel closes the internal channel and returns true. If the
+	// cancelable is already canceled this returns false
+	Cancel() bool
+	// SendResult sends a value to channel listeners
+	SendResult(value interface{})
+	// SendError sends an error to channel listeners
+	SendError(err error)
+	// Receive returns the result channel
+	Receive() <-chan Result
+	// Start runs the userdefined handler func. The specified context is
+	// passed through to the handler func
+	Start(ctx context.Context) GoResultCancelable
+	// StartBackground starts the cancelable on a goroutine
+	StartBackground(ctx context.Context) GoResultCancelable
+	// LastResult returns the last result sent successfully on the channel
+	LastResult() Result
+	// Cause returns the error the cancelable was canceled with, if any
+	Cause() error
+	// IsCanceled returns true if the cancelable is canceled otherwise
+	// returns false
+	IsCanceled() bool
+}
+
+// GoRaceResult creates a cancelable whose handler reports values and errors separately, so consumers
+// check Result.Err instead of type switching on the received value
+func GoRaceResult(handler func(ctx context.Context, cancelable GoResultCancelable), opts ...Option) GoResultCancelable {
+	rc := &goResultCancelable{}
+	rc.wrapper = newWrapper[Result, GoResultCancelable](rc, func(ctx context.Context, _ GoCancelable) {
+		handler(ctx, rc)
+	}, opts...)
+	return rc
+}
+
+// Implementation of GoResultCancelable on top of a regular cancelable
+type goResultCancelable struct {
+	*wrapper[Result, GoResultCancelable]
+}
+
+// SendResult sends the value wrapped in a Result
+func (rc *goResultCancelable) SendResult(value interface{}) {
+	rc.gc.Send(Result{Value: value})
+}
+
+// SendError sends the error wrapped in a Result
+func (rc *goResultCancelable) SendError(err error) {
+	rc.gc.Send(Result{Err: err})
+}
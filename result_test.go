@@ -0,0 +1,67 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceResult() {
+	failure := errors.New("request failed")
+	cancelable := GoRaceResult(func(ctx context.Context, cancelable GoResultCancelable) {
+		cancelable.SendResult(work(ctx))
+		cancelable.SendError(failure)
+	})
+	cancelable.Start(context.Background())
+
+	var results []Result
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]Result{{Value: true}, {Err: failure}}, results)
+	suite.Equal(Result{Err: failure}, cancelable.LastResult(), "cancelable.LastResult() should be the last Result")
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceResultCancel() {
+	cancelable := GoRaceResult(func(ctx context.Context, cancelable GoResultCancelable) {
+		for i := 0; i < 50; i++ {
+			cancelable.SendResult(i)
+		}
+	})
+	cancelable.Start(context.Background())
+
+	suite.Equal(Result{Value: 0}, <-cancelable.Receive())
+	cancelable.Cancel()
+
+	for range cancelable.Receive() {
+	}
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceResultCause() {
+	cancelable := GoRaceResult(func(ctx context.Context, cancelable GoResultCancelable) {
+		panic("boom")
+	}).Start(context.Background())
+
+	for range cancelable.Receive() {
+	}
+	var panicErr *PanicError
+	suite.ErrorAs(cancelable.Cause(), &panicErr, "cancelable.Cause() should be the handler's panic")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceResultUnexpectedType() {
+	cancelable := GoRaceResult(func(ctx context.Context, cancelable GoResultCancelable) {
+		cancelable.SendResult(1)
+		<-ctx.Done()
+	}, WithResultTimestamp())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cancelable.Start(ctx)
+
+	for range cancelable.Receive() {
+		suite.Fail("a Timestamped value should not be received as a Result")
+	}
+	suite.ErrorIs(cancelable.Cause(), ErrUnexpectedType)
+}
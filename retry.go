@@ -0,0 +1,56 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+// GoRaceRetry creates a cancelable whose handler is called up to attempts times. The handler is called
+// again when it returns without sending a result or after calling Fail, waiting backoff(attempt)
+// in between. The error passed to Fail on the last attempt is sent to consumers
+func GoRaceRetry(attempts int, backoff func(attempt int) time.Duration, handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoCancelable {
+	gc := newGoCancelable(handler, opts...)
+	gc.attempts = attempts
+	gc.backoff = backoff
+	return gc.handle()
+}
+
+// Fail records err as the failure of the current handler run
+func (gc *goCancelable) Fail(err error) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.failure = err
+}
+
+// Calls the handler until a run sends a result without failing or the attempts are used up. Stops
+// waiting between attempts when the cancelable is canceled or ctx is done
+func (gc *goCancelable) run(ctx context.Context) {
+	for attempt := 1; ; attempt++ {
+		gc.handler(ctx, gc)
+		gc.mu.Lock()
+		failure, sent := gc.failure, gc.sent
+		gc.failure, gc.sent = nil, false
+		gc.mu.Unlock()
+		if failure == nil && sent {
+			return
+		}
+		if attempt >= gc.attempts {
+			if failure != nil {
+				gc.Send(failure)
+			}
+			return
+		}
+		if gc.backoff != nil {
+			timer := time.NewTimer(gc.backoff(attempt))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-gc.quit:
+				timer.Stop()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gorace
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceRetry() {
+	failure := errors.New("flaky api")
+	attempts := 0
+	cancelable := GoRaceRetry(3, func(attempt int) time.Duration {
+		return time.Duration(attempt) * time.Millisecond
+	}, func(ctx context.Context, cancelable GoCancelable) {
+		attempts++
+		switch attempts {
+		case 1:
+			cancelable.Fail(failure)
+		case 2:
+			// Returning without a result is retried as well
+		default:
+			cancelable.Send(work(ctx))
+		}
+	})
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{true}, results)
+	suite.Equal(3, attempts, "the handler should be called until it succeeds")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRetryExhausted() {
+	attempts := 0
+	cancelable := GoRaceRetry(3, nil, func(ctx context.Context, cancelable GoCancelable) {
+		attempts++
+		cancelable.Fail(errors.New("flaky api"))
+	})
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{errors.New("flaky api")}, results, "the last error should be received")
+	suite.Equal(3, attempts, "the handler should be called attempts times")
+}
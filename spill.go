@@ -0,0 +1,43 @@
+package gorace
+
+// SpillPolicy decides what Send does when the spillover queue of WithSpillover is full
+type SpillPolicy int
+
+const (
+	// SpillBlock makes Send wait until the next queued result was sent
+	SpillBlock SpillPolicy = iota
+	// SpillDrop drops the result and counts it in Dropped
+	SpillDrop
+)
+
+// Sends the prepared result if the channel has room and nothing is queued ahead of it, otherwise queues it
+// for the pump. Spilled results share the queue of SendPriority at priority 0. The lock is released while
+// SpillBlock waits for room. Requires locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) spillover(result interface{}) {
+	if gc.queued() == 0 {
+		select {
+		case gc.send <- result:
+			gc.delivered(result)
+			return
+		default:
+		}
+	}
+	for gc.spillMax > 0 && gc.queued() >= gc.spillMax {
+		if gc.spillPolicy == SpillDrop {
+			gc.dropped.Add(1)
+			return
+		}
+		// Wait for the pump to make room, without holding up Cancel
+		gc.mu.Unlock()
+		select {
+		case <-gc.prioRoom:
+		case <-gc.quit:
+		}
+		gc.mu.Lock()
+		if gc.canceled.Load() {
+			gc.dropped.Add(1)
+			return
+		}
+	}
+	gc.enqueue(result, 0, true)
+}
@@ -0,0 +1,81 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceWithSpillover() {
+	sent := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 100; i++ {
+			cancelable.Send(i)
+		}
+		close(sent)
+	}, WithSpillover(0, SpillBlock))
+	cancelable.Start(context.Background())
+	<-sent // no send blocked even though nothing was received
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Len(results, 100, "every spilled result should be sent")
+	for i, result := range results {
+		suite.Equal(i, result, "spilled results should keep their order")
+	}
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithSpilloverDrop() {
+	sent := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 10; i++ {
+			cancelable.Send(i)
+		}
+		close(sent)
+	}, WithSpillover(2, SpillDrop))
+	cancelable.Start(context.Background())
+	<-sent
+
+	_, dropped := cancelable.Stats()
+	suite.Equal(int64(7), dropped, "results past the channel and the full queue should be dropped")
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{0, 1, 2}, results)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithSpilloverBlock() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 10; i++ {
+			cancelable.Send(i)
+		}
+	}, WithSpillover(2, SpillBlock))
+	cancelable.Start(context.Background())
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+	suite.Equal([]interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, results, "a full queue should block the sender, not drop")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWithSpilloverCancel() {
+	sent := make(chan struct{})
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		for i := 0; i < 5; i++ {
+			cancelable.Send(i)
+		}
+		close(sent)
+		<-ctx.Done()
+	}, WithSpillover(0, SpillBlock), WithBuffer(0))
+	cancelable.Start(context.Background())
+	<-sent
+	cancelable.Cancel()
+
+	suite.Eventually(func() bool {
+		delivered, dropped := cancelable.Stats()
+		return delivered == 0 && dropped == 5
+	}, time.Second, time.Millisecond, "results still queued on cancel should be dropped")
+}
@@ -0,0 +1,107 @@
+package gorace
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is a point in time view of a cancelable, see Snapshot
+type Status struct {
+	ID             uint64        `json:"id"`
+	Name           string        `json:"name,omitempty"`
+	State          string        `json:"state"`
+	Started        bool          `json:"started"`
+	Canceled       bool          `json:"canceled"`
+	Elapsed        time.Duration `json:"elapsed"`
+	Pending        int           `json:"pending"`
+	DeliveredCount uint64        `json:"deliveredCount"`
+}
+
+// Snapshot returns the current status of the cancelable. State is one of "idle", "running", "completed"
+// and "canceled"
+func (gc *goCancelable) Snapshot() Status {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	status := Status{
+		ID:             gc.id,
+		Name:           gc.name,
+		State:          "idle",
+		Started:        gc.started,
+		Canceled:       gc.canceled.Load(),
+		Pending:        len(gc.send),
+		DeliveredCount: gc.deliveries.Load(),
+	}
+	switch {
+	case gc.reason == ReasonCompleted:
+		status.State = "completed"
+	case status.Canceled:
+		status.State = "canceled"
+	case gc.started:
+		status.State = "running"
+	}
+	if gc.started && status.Canceled {
+		status.Elapsed = gc.stoppedAt.Sub(gc.startedAt)
+	} else if gc.started {
+		status.Elapsed = time.Since(gc.startedAt)
+	}
+	return status
+}
+
+// Registry tracks live cancelables configured with WithRegistry. Cancelables are removed once canceled.
+// A Registry is an http.Handler serving the snapshots of its cancelables as JSON, for debug endpoints
+// like GET /debug/gorace
+type Registry struct {
+	mu          sync.Mutex
+	cancelables map[uint64]*goCancelable
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{cancelables: make(map[uint64]*goCancelable)}
+}
+
+// Adds a cancelable until it's canceled
+func (r *Registry) add(gc *goCancelable) {
+	r.mu.Lock()
+	r.cancelables[gc.id] = gc
+	r.mu.Unlock()
+	gc.hooks = append(gc.hooks, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.cancelables, gc.id)
+	})
+}
+
+// Snapshot returns the status of every live cancelable ordered by ID
+func (r *Registry) Snapshot() []Status {
+	r.mu.Lock()
+	cancelables := make([]*goCancelable, 0, len(r.cancelables))
+	for _, gc := range r.cancelables {
+		cancelables = append(cancelables, gc)
+	}
+	r.mu.Unlock()
+	sort.Slice(cancelables, func(i, j int) bool { return cancelables[i].id < cancelables[j].id })
+	statuses := make([]Status, len(cancelables))
+	for i, gc := range cancelables {
+		statuses[i] = gc.Snapshot()
+	}
+	return statuses
+}
+
+// ServeHTTP writes the registry snapshot as a JSON array
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Stats returns the number of results delivered to the channel and the number dropped, see Dropped. Both
+// count results, so a batch or group counts each result in it whether it was delivered or dropped. They
+// are counted atomically and can be read at any time, including while a Send is blocked
+func (gc *goCancelable) Stats() (delivered, dropped int64) {
+	return int64(gc.deliveries.Load()), int64(gc.dropped.Load())
+}
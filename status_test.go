@@ -0,0 +1,80 @@
+package gorace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceSnapshot() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(true)
+		<-ctx.Done()
+	}, WithName("fetch"))
+	suite.Equal("idle", cancelable.Snapshot().State)
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool { return cancelable.Snapshot().Pending == 1 }, time.Second, time.Millisecond)
+
+	status := cancelable.Snapshot()
+
+	suite.Equal("fetch", status.Name)
+	suite.Equal("running", status.State)
+	suite.True(status.Started, "status.Started should be true")
+	suite.Equal(uint64(1), status.DeliveredCount)
+	cancelable.Cancel()
+	suite.Equal("canceled", cancelable.Snapshot().State)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceRegistry() {
+	registry := NewRegistry()
+	live := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		<-ctx.Done()
+	}, WithName("live"), WithRegistry(registry))
+	done := GoRace(func(ctx context.Context, cancelable GoCancelable) {}, WithName("done"), WithRegistry(registry))
+	live.Start(context.Background())
+	defer live.Cancel()
+	done.Run(context.Background())
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/gorace", nil))
+
+	var statuses []Status
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	suite.Len(statuses, 1, "canceled cancelables should be removed from the registry")
+	suite.Equal("live", statuses[0].Name)
+	suite.Equal("running", statuses[0].State)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStats() {
+	cancelable := GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.SendTimeout(2, time.Millisecond) // the channel is full
+		<-ctx.Done()
+	})
+	cancelable.Start(context.Background())
+	suite.Eventually(func() bool {
+		_, dropped := cancelable.Stats()
+		return dropped == 1
+	}, time.Second, time.Millisecond, "a timed out send should be dropped")
+	cancelable.Cancel()
+	cancelable.Send(3)
+
+	delivered, dropped := cancelable.Stats()
+	suite.Equal(int64(1), delivered, "only the first result should be delivered")
+	suite.Equal(int64(2), dropped, "sends after the cancel should be dropped")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStatsBatch() {
+	cancelable := GoRaceBatch(3, time.Hour, func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send(2)
+		cancelable.Send(3)
+	})
+	cancelable.Start(context.Background())
+	for range cancelable.Receive() {
+	}
+
+	delivered, _ := cancelable.Stats()
+	suite.Equal(int64(3), delivered, "each result of a batch should count as delivered")
+}
@@ -0,0 +1,60 @@
+package gorace
+
+import "context"
+
+// GoTypedCancelable contract. Equivalent to the GoCancelable passed to its
+// handler except results are received as T
+type GoTypedCancelable[T any] interface {
+	// Cancel closes the internal channel and returns true. If the
+	// cancelable is already canceled this returns false
+	Cancel() bool
+	// Receive returns the typed result channel
+	Receive() <-chan T
+	// Start runs the userdefined handler func. The specified context is
+	// passed through to the handler func
+	Start(ctx context.Context) GoTypedCancelable[T]
+	// StartBackground starts the cancelable on a goroutine
+	StartBackground(ctx context.Context) GoTypedCancelable[T]
+	// LastResult returns the last result sent successfully on the channel
+	LastResult() T
+	// Cause returns the error the cancelable was canceled with, if any
+	Cause() error
+	// IsCanceled returns true if the cancelable is canceled otherwise
+	// returns false
+	IsCanceled() bool
+}
+
+// GoRaceBool creates a cancelable whose results are received as bool. A result of another type cancels
+// the cancelable with an error wrapping ErrUnexpectedType
+func GoRaceBool(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoTypedCancelable[bool] {
+	return newTyped[bool](handler, opts...)
+}
+
+// GoRaceInt creates a cancelable whose results are received as int. A result of another type cancels
+// the cancelable with an error wrapping ErrUnexpectedType
+func GoRaceInt(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoTypedCancelable[int] {
+	return newTyped[int](handler, opts...)
+}
+
+// GoRaceString creates a cancelable whose results are received as string. A result of another type
+// cancels the cancelable with an error wrapping ErrUnexpectedType
+func GoRaceString(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoTypedCancelable[string] {
+	return newTyped[string](handler, opts...)
+}
+
+// GoRaceBytes creates a cancelable whose results are received as []byte. A result of another type
+// cancels the cancelable with an error wrapping ErrUnexpectedType
+func GoRaceBytes(handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) GoTypedCancelable[[]byte] {
+	return newTyped[[]byte](handler, opts...)
+}
+
+func newTyped[T any](handler func(ctx context.Context, cancelable GoCancelable), opts ...Option) *goTypedCancelable[T] {
+	tc := &goTypedCancelable[T]{}
+	tc.wrapper = newWrapper[T, GoTypedCancelable[T]](tc, handler, opts...)
+	return tc
+}
+
+// Implementation of GoTypedCancelable on top of a regular cancelable
+type goTypedCancelable[T any] struct {
+	*wrapper[T, GoTypedCancelable[T]]
+}
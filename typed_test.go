@@ -0,0 +1,53 @@
+package gorace
+
+import (
+	"context"
+	"time"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceBool() {
+	cancelable := GoRaceBool(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(work(ctx))
+	})
+	cancelable.Start(context.Background())
+
+	var results []bool
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]bool{true}, results)
+	suite.Equal(true, cancelable.LastResult())
+	suite.Equal(true, cancelable.IsCanceled(), "cancelable.IsCanceled() should be true")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceTypedMismatch() {
+	cancelable := GoRaceInt(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send(1)
+		cancelable.Send("two")
+		<-ctx.Done()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cancelable.Start(ctx)
+
+	var results []int
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]int{1}, results)
+	suite.ErrorIs(cancelable.Cause(), ErrUnexpectedType)
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceStringAndBytes() {
+	text := GoRaceString(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send("done")
+	}).Start(context.Background())
+	data := GoRaceBytes(func(ctx context.Context, cancelable GoCancelable) {
+		cancelable.Send([]byte("done"))
+	}).Start(context.Background())
+
+	suite.Equal("done", <-text.Receive())
+	suite.Equal([]byte("done"), <-data.Receive())
+}
@@ -0,0 +1,52 @@
+package gorace
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Sends the result on the channel unless the cancelable is canceled. The lock is released while waiting for
+// room, so a full channel holds up neither Cancel nor other callers, and held again on return. Returns false
+// if the result was dropped because the cancelable was canceled, before or while waiting. With a send
+// watchdog, a warning with the stack of the sending goroutine is logged if the send blocks for longer than
+// the watchdog duration. Requires locks prior to this method call to remain concurrency-safe.
+func (gc *goCancelable) push(result interface{}) bool {
+	if gc.canceled.Load() {
+		return false
+	}
+	select {
+	case gc.send <- result:
+		return true
+	default:
+	}
+	var timer *time.Timer
+	if gc.watchdog > 0 {
+		stack := debug.Stack()
+		timer = time.AfterFunc(gc.watchdog, func() {
+			gc.warnBlocked(stack)
+		})
+	}
+	gc.mu.Unlock()
+	sent := gc.pushUnlocked(result, nil)
+	gc.mu.Lock()
+	if timer != nil {
+		timer.Stop()
+	}
+	return sent
+}
+
+// Logs a warning for a send blocked longer than the watchdog duration. The name and logger are only set
+// while idle, reading them can't race
+func (gc *goCancelable) warnBlocked(stack []byte) {
+	name := "cancelable"
+	if gc.name != "" {
+		name = fmt.Sprintf("cancelable %q", gc.name)
+	}
+	msg := fmt.Sprintf("gorace: %s blocked on Send for more than %s, is the channel drained?", name, gc.watchdog)
+	if gc.logger != nil {
+		gc.logger.Warn(msg, "stack", string(stack))
+	} else {
+		warnf("%s\n%s", msg, stack)
+	}
+}
@@ -0,0 +1,56 @@
+package gorace
+
+import (
+	"context"
+	"sync"
+)
+
+// GoRaceWorkers creates a cancelable that runs n copies of the handler concurrently, each with its
+// worker index. The cancelable completes once every worker returned
+func GoRaceWorkers(n int, handler func(ctx context.Context, worker int, cancelable GoCancelable), opts ...Option) GoCancelable {
+	return GoRace(func(ctx context.Context, cancelable GoCancelable) {
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				handler(ctx, worker, cancelable)
+			}(i)
+		}
+		wg.Wait()
+	}, opts...)
+}
+
+// Assigns the result a sequence number as soon as Send is called and sends results strictly in sequence
+// order. Results whose turn hasn't come yet are parked in the reorder buffer and sent by the caller
+// that fills the gap. Returns false if the result was dropped, a parked result counts as sent
+func (gc *goCancelable) sendOrdered(result interface{}) bool {
+	seq := gc.seq.Add(1) - 1
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.canceled.Load() {
+		gc.dropped.Add(1)
+		return false
+	}
+	if gc.reorder == nil {
+		gc.reorder = make(map[uint64]interface{})
+	}
+	gc.reorder[seq] = result
+	// Sends may release the lock while waiting, only one caller sends at a time to keep the order
+	if gc.ordering {
+		return true
+	}
+	gc.ordering = true
+	defer func() { gc.ordering = false }()
+	sent := true
+	for next, ok := gc.reorder[gc.nextSeq]; ok; next, ok = gc.reorder[gc.nextSeq] {
+		current := gc.nextSeq
+		delete(gc.reorder, current)
+		gc.nextSeq++
+		if !gc.sendLocked(next) {
+			gc.dropped.Add(1)
+			sent = sent && current != seq
+		}
+	}
+	return sent
+}
@@ -0,0 +1,53 @@
+package gorace
+
+import (
+	"context"
+	"runtime"
+)
+
+func (suite *GoRaceTestSuite) TestGoRaceWorkers() {
+	cancelable := GoRaceWorkers(4, func(ctx context.Context, worker int, cancelable GoCancelable) {
+		cancelable.Send(worker)
+	})
+	cancelable.Start(context.Background())
+
+	workers := map[interface{}]bool{}
+	for result := range cancelable.Receive() {
+		workers[result] = true
+	}
+
+	suite.Equal(map[interface{}]bool{0: true, 1: true, 2: true, 3: true}, workers, "every worker should run")
+}
+
+func (suite *GoRaceTestSuite) TestGoRaceWorkersWithTotalOrder() {
+	const workers = 8
+	turns := make([]chan struct{}, workers+1)
+	for i := range turns {
+		turns[i] = make(chan struct{})
+	}
+	cancelable := GoRaceWorkers(workers, func(ctx context.Context, worker int, cancelable GoCancelable) {
+		gc := cancelable.(*goCancelable)
+		<-turns[worker]
+		// Send from another goroutine so the sends race for the lock, and pass the turn as soon as
+		// the logical send point was reached
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			cancelable.Send(worker)
+		}()
+		for gc.seq.Load() <= uint64(worker) {
+			runtime.Gosched()
+		}
+		close(turns[worker+1])
+		<-done
+	}, WithTotalOrder())
+	cancelable.Start(context.Background())
+	close(turns[0])
+
+	var results []interface{}
+	for result := range cancelable.Receive() {
+		results = append(results, result)
+	}
+
+	suite.Equal([]interface{}{0, 1, 2, 3, 4, 5, 6, 7}, results, "results should be received in logical send order")
+}